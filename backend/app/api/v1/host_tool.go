@@ -1,9 +1,20 @@
 package v1
 
 import (
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/1Panel-dev/1Panel/backend/app/api/v1/helper"
 	"github.com/1Panel-dev/1Panel/backend/app/dto/request"
+	"github.com/1Panel-dev/1Panel/backend/app/service"
+	"github.com/1Panel-dev/1Panel/backend/buserr"
 	"github.com/1Panel-dev/1Panel/backend/constant"
+	"github.com/1Panel-dev/1Panel/backend/global"
 	"github.com/gin-gonic/gin"
 )
 
@@ -65,11 +76,15 @@ func (b *BaseApi) OperateTool(c *gin.Context) {
 	if err := helper.CheckBindAndValidate(&req, c); err != nil {
 		return
 	}
-	err := hostToolService.OperateTool(req)
+	res, err := hostToolService.OperateTool(req, c.ClientIP())
 	if err != nil {
 		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
 		return
 	}
+	if req.Operate == "enable" || req.Operate == "disable" {
+		helper.SuccessWithData(c, res)
+		return
+	}
 	helper.SuccessWithOutData(c)
 }
 
@@ -96,6 +111,50 @@ func (b *BaseApi) OperateToolConfig(c *gin.Context) {
 	helper.SuccessWithData(c, config)
 }
 
+// @Tags Host tool
+// @Summary Set supervisor inet config
+// @Description 配置 supervisord XML-RPC 控制接口
+// @Accept json
+// @Param request body request.SupervisorInetConfigReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/inet [post]
+// @x-panel-log {"bodyKeys":["bindAddress"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"配置 Supervisor 控制接口 [bindAddress]","formatEN":"set supervisor inet control interface to [bindAddress]"}
+func (b *BaseApi) SetSupervisorInetConfig(c *gin.Context) {
+	var req request.SupervisorInetConfigReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	if err := hostToolService.SetSupervisorInetConfig(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Set supervisor binaries
+// @Description 配置 supervisord/supervisorctl 的自定义安装路径
+// @Accept json
+// @Param request body request.SupervisorBinariesReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/binaries [post]
+// @x-panel-log {"bodyKeys":["supervisordBinary","supervisorctlBinary"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"配置 Supervisor 二进制路径 [supervisordBinary] [supervisorctlBinary]","formatEN":"set supervisor binary paths to [supervisordBinary] [supervisorctlBinary]"}
+func (b *BaseApi) SetSupervisorBinaries(c *gin.Context) {
+	var req request.SupervisorBinariesReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	if err := hostToolService.SetSupervisorBinaries(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
 // @Tags Host tool
 // @Summary Get tool
 // @Description 获取主机工具日志
@@ -141,6 +200,51 @@ func (b *BaseApi) OperateProcess(c *gin.Context) {
 	helper.SuccessWithOutData(c)
 }
 
+// @Tags Host tool
+// @Summary Reread or reload supervisor configs
+// @Description 预览或应用 Supervisor 配置重载
+// @Accept json
+// @Param request body request.SupervisorReloadReq true "request"
+// @Success 200 {object} response.SupervisorReread
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/reload [post]
+// @x-panel-log {"bodyKeys":["operate"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"[operate] Supervisor 配置","formatEN":"[operate] supervisor configs"}
+func (b *BaseApi) OperateSupervisorReload(c *gin.Context) {
+	var req request.SupervisorReloadReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	result, err := hostToolService.OperateSupervisorReload(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, result)
+}
+
+// @Tags Host tool
+// @Summary Test-run a supervisor process command
+// @Description 试运行 Supervisor 进程命令
+// @Accept json
+// @Param request body request.SupervisorProcessTestReq true "request"
+// @Success 200 {object} response.SupervisorProcessTestResult
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/test [post]
+func (b *BaseApi) TestProcessCommand(c *gin.Context) {
+	var req request.SupervisorProcessTestReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	result, err := hostToolService.TestSupervisorProcessCommand(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, result)
+}
+
 // @Tags Host tool
 // @Summary Get Supervisor process config
 // @Description 获取 Supervisor 进程配置
@@ -157,6 +261,86 @@ func (b *BaseApi) GetProcess(c *gin.Context) {
 	helper.SuccessWithData(c, configs)
 }
 
+// @Tags Host tool
+// @Summary Operate Supervisor event listener
+// @Description 操作 Supervisor 事件监听器
+// @Accept json
+// @Param request body request.SupervisorEventListenerConfig true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/eventlistener [post]
+// @x-panel-log {"bodyKeys":["operate"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"[operate] Supervisor 事件监听器 ","formatEN":"[operate] Supervisor event listener"}
+func (b *BaseApi) OperateEventListener(c *gin.Context) {
+	var req request.SupervisorEventListenerConfig
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	err := hostToolService.OperateSupervisorEventListener(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Get Supervisor event listeners
+// @Description 获取 Supervisor 事件监听器列表
+// @Accept json
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/eventlistener [get]
+func (b *BaseApi) GetEventListeners(c *gin.Context) {
+	listeners, err := hostToolService.GetSupervisorEventListeners()
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, listeners)
+}
+
+// @Tags Host tool
+// @Summary Set service custom command
+// @Description 设置服务自定义操作命令
+// @Accept json
+// @Param request body request.ServiceCustomCommandReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/command [post]
+// @x-panel-log {"bodyKeys":["serviceName","action"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"设置 [serviceName] 的 [action] 自定义命令","formatEN":"set custom [action] command for [serviceName]"}
+func (b *BaseApi) SetServiceCustomCommand(c *gin.Context) {
+	var req request.ServiceCustomCommandReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	if err := hostToolService.SetServiceCustomCommand(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Get Supervisor process templates
+// @Description 获取 Supervisor 进程模版
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/template [get]
+func (b *BaseApi) GetProcessTemplates(c *gin.Context) {
+	helper.SuccessWithData(c, hostToolService.GetSupervisorProcessTemplates())
+}
+
+// @Tags Host tool
+// @Summary Get supported init systems
+// @Description 获取支持的初始化系统
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/init-systems [get]
+func (b *BaseApi) GetInitSystems(c *gin.Context) {
+	helper.SuccessWithData(c, hostToolService.GetInitSystems())
+}
+
 // @Tags Host tool
 // @Summary Get Supervisor process config
 // @Description 操作 Supervisor 进程文件
@@ -178,3 +362,263 @@ func (b *BaseApi) GetProcessFile(c *gin.Context) {
 	}
 	helper.SuccessWithData(c, content)
 }
+
+// @Tags Host tool
+// @Summary Validate Supervisor process config
+// @Description 校验 Supervisor 进程配置
+// @Accept json
+// @Param request body request.SupervisorProcessFileReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/file/validate [post]
+func (b *BaseApi) ValidateProcessConfig(c *gin.Context) {
+	var req request.SupervisorProcessFileReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	result, err := hostToolService.ValidateSupervisorProcessConfig(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, result)
+}
+
+// @Tags Host tool
+// @Summary Resolve service names
+// @Description 批量解析服务名称
+// @Accept json
+// @Param request body request.ServiceNameBatchReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/names [post]
+func (b *BaseApi) GetServiceNames(c *gin.Context) {
+	var req request.ServiceNameBatchReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	helper.SuccessWithData(c, hostToolService.GetServiceNames(req.Keywords))
+}
+
+// @Tags Host tool
+// @Summary Get a service's active status
+// @Description 获取指定关键字的服务运行状态
+// @Accept json
+// @Param keyword query string true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/status [get]
+func (b *BaseApi) GetServiceStatus(c *gin.Context) {
+	keyword := c.Query("keyword")
+	status, err := hostToolService.GetServiceStatus(keyword)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, status)
+}
+
+// @Tags Host tool
+// @Summary Find services
+// @Description 发现系统服务
+// @Accept json
+// @Param request body request.ServiceDiscoveryReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/find [post]
+func (b *BaseApi) FindServices(c *gin.Context) {
+	var req request.ServiceDiscoveryReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	services, err := hostToolService.FindServices(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, services)
+}
+
+// @Tags Host tool
+// @Summary Pin a service name
+// @Description 手动指定关键字对应的服务名称
+// @Accept json
+// @Param request body request.ServiceNamePinReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/pin [post]
+// @x-panel-log {"bodyKeys":["keyword","serviceName"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"将 [keyword] 指定为 [serviceName]","formatEN":"pin [keyword] to [serviceName]"}
+func (b *BaseApi) PinServiceName(c *gin.Context) {
+	var req request.ServiceNamePinReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	if err := hostToolService.PinServiceName(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Unpin a service name
+// @Description 取消关键字的服务名称指定
+// @Accept json
+// @Param keyword query string true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/pin [delete]
+// @x-panel-log {"bodyKeys":["keyword"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"取消 [keyword] 的指定","formatEN":"unpin [keyword]"}
+func (b *BaseApi) UnpinServiceName(c *gin.Context) {
+	keyword := c.Query("keyword")
+	if err := hostToolService.UnpinServiceName(keyword); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Add a service alias
+// @Description 为关键字添加额外的候选服务名称
+// @Accept json
+// @Param request body request.ServiceAliasReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/service/alias [post]
+// @x-panel-log {"bodyKeys":["keyword","names"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"为 [keyword] 添加候选服务名称","formatEN":"add service aliases for [keyword]"}
+func (b *BaseApi) AddServiceAlias(c *gin.Context) {
+	var req request.ServiceAliasReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	if err := hostToolService.AddServiceAlias(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Download Supervisor process log
+// @Description 下载 Supervisor 进程日志
+// @Accept json
+// @Param name query string true "request"
+// @Param file query string true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/log/download [get]
+func (b *BaseApi) DownloadProcessLog(c *gin.Context) {
+	req := request.SupervisorProcessFileReq{
+		Name: c.Query("name"),
+		File: c.Query("file"),
+	}
+	logPath, err := hostToolService.GetSupervisorProcessLogPath(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	file, err := os.Open(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, buserr.WithName(constant.ErrFileNotFound, req.File))
+			return
+		}
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	defer file.Close()
+
+	filename := req.Name + "_" + time.Now().Format("20060102150405") + "_" + req.File
+	gzipOut := strings.Contains(c.GetHeader("Accept-Encoding"), "gzip")
+	if gzipOut {
+		filename += ".gz"
+		c.Header("Content-Encoding", "gzip")
+	} else if info, statErr := file.Stat(); statErr == nil {
+		c.Header("Content-Length", strconv.FormatInt(info.Size(), 10))
+	}
+	c.Header("Content-Disposition", "attachment; filename*=utf-8''"+url.PathEscape(filename))
+	if err := service.StreamSupervisorProcessLog(c.Writer, file, gzipOut); err != nil {
+		global.LOG.Errorf("stream supervisor process log failed, err: %v", err)
+	}
+}
+
+// @Tags Host tool
+// @Summary Export Supervisor process configs
+// @Description 导出 Supervisor 进程配置
+// @Accept json
+// @Param request body request.SupervisorConfigsBackupReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/export [post]
+func (b *BaseApi) ExportProcessConfigs(c *gin.Context) {
+	var req request.SupervisorConfigsBackupReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	tarPath, err := hostToolService.ExportSupervisorProcessConfigs(req)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	file, err := os.Open(tarPath)
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	defer file.Close()
+	defer os.RemoveAll(path.Dir(tarPath))
+
+	c.Header("Content-Disposition", "attachment; filename*=utf-8''"+url.PathEscape(path.Base(tarPath)))
+	if info, statErr := file.Stat(); statErr == nil {
+		c.Header("Content-Length", strconv.FormatInt(info.Size(), 10))
+	}
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		global.LOG.Errorf("stream supervisor configs export failed, err: %v", err)
+	}
+}
+
+// @Tags Host tool
+// @Summary Import Supervisor process configs
+// @Description 导入 Supervisor 进程配置
+// @Accept json
+// @Param request body request.SupervisorConfigsRestoreReq true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/import [post]
+// @x-panel-log {"bodyKeys":["tarPath"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"导入 Supervisor 进程配置 [tarPath]","formatEN":"import supervisor process configs from [tarPath]"}
+func (b *BaseApi) ImportProcessConfigs(c *gin.Context) {
+	var req request.SupervisorConfigsRestoreReq
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+	if err := hostToolService.ImportSupervisorProcessConfigs(req); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags Host tool
+// @Summary Follow Supervisor process log
+// @Description 实时跟踪 Supervisor 进程日志
+// @Accept json
+// @Param name query string true "request"
+// @Param file query string true "request"
+// @Security ApiKeyAuth
+// @Router /host/tool/supervisor/process/log/ws [get]
+func (b *BaseApi) FollowProcessLog(c *gin.Context) {
+	wsConn, err := upGrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		global.LOG.Errorf("gin context http handler failed, err: %v", err)
+		return
+	}
+	req := request.SupervisorProcessFileReq{
+		Name: c.Query("name"),
+		File: c.Query("file"),
+	}
+	if err := hostToolService.FollowSupervisorProcessLog(wsConn, req); err != nil {
+		_ = wsConn.WriteMessage(1, []byte(err.Error()))
+		return
+	}
+}
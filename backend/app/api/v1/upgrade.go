@@ -22,6 +22,21 @@ func (b *BaseApi) GetUpgradeInfo(c *gin.Context) {
 	helper.SuccessWithData(c, info)
 }
 
+// @Tags System Setting
+// @Summary Check for an available upgrade
+// @Description 检查是否有新版本，不获取更新说明，适合周期性轮询
+// @Success 200 {object} dto.CheckUpgradeInfo
+// @Security ApiKeyAuth
+// @Router /settings/upgrade/check [get]
+func (b *BaseApi) CheckUpgrade(c *gin.Context) {
+	hasUpdate, latest, err := upgradeService.CheckUpgrade()
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, dto.CheckUpgradeInfo{HasUpdate: hasUpdate, LatestVersion: latest})
+}
+
 // @Tags System Setting
 // @Summary Load release notes by version
 // @Description 获取版本 release notes
@@ -65,3 +80,65 @@ func (b *BaseApi) Upgrade(c *gin.Context) {
 	}
 	helper.SuccessWithData(c, nil)
 }
+
+// @Tags System Setting
+// @Summary Cancel an in-progress upgrade
+// @Description 取消正在进行的系统更新
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /settings/upgrade/cancel [post]
+// @x-panel-log {"bodyKeys":[],"paramKeys":[],"BeforeFunctions":[],"formatZH":"取消系统更新","formatEN":"cancel system upgrade"}
+func (b *BaseApi) CancelUpgrade(c *gin.Context) {
+	if err := upgradeService.CancelUpgrade(); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
+
+// @Tags System Setting
+// @Summary Get upgrade progress
+// @Description 获取系统更新进度
+// @Success 200 {object} dto.UpgradeStatus
+// @Security ApiKeyAuth
+// @Router /settings/upgrade/status [get]
+func (b *BaseApi) GetUpgradeStatus(c *gin.Context) {
+	helper.SuccessWithData(c, upgradeService.UpgradeStatus())
+}
+
+// @Tags System Setting
+// @Summary List available versions
+// @Description 列出可用于更新（或指定版本回退）的所有版本
+// @Success 200 {array} dto.VersionInfo
+// @Security ApiKeyAuth
+// @Router /settings/upgrade/versions [get]
+func (b *BaseApi) ListVersions(c *gin.Context) {
+	versions, err := upgradeService.ListVersions()
+	if err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithData(c, versions)
+}
+
+// @Tags System Setting
+// @Summary Set upgrade proxy
+// @Description 设置系统更新代理
+// @Accept json
+// @Param request body dto.UpgradeProxy true "request"
+// @Success 200
+// @Security ApiKeyAuth
+// @Router /settings/upgrade/proxy [post]
+// @x-panel-log {"bodyKeys":["proxyUrl"],"paramKeys":[],"BeforeFunctions":[],"formatZH":"设置系统更新代理 => [proxyUrl]","formatEN":"set upgrade proxy => [proxyUrl]"}
+func (b *BaseApi) SetUpgradeProxy(c *gin.Context) {
+	var req dto.UpgradeProxy
+	if err := helper.CheckBindAndValidate(&req, c); err != nil {
+		return
+	}
+
+	if err := upgradeService.SetUpgradeProxy(req.ProxyUrl); err != nil {
+		helper.ErrorWithDetail(c, constant.CodeErrInternalServer, constant.ErrTypeInternalServer, err)
+		return
+	}
+	helper.SuccessWithOutData(c)
+}
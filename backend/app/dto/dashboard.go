@@ -30,6 +30,9 @@ type OsInfo struct {
 	PlatformFamily string `json:"platformFamily"`
 	KernelArch     string `json:"kernelArch"`
 	KernelVersion  string `json:"kernelVersion"`
+	// ServiceManager is the init system's control CLI detected on this host
+	// (e.g. "systemd", "openrc", "sysvinit"), or "" when none was found.
+	ServiceManager string `json:"serviceManager"`
 }
 
 type DashboardCurrent struct {
@@ -2,7 +2,44 @@ package request
 
 type HostToolReq struct {
 	Type    string `json:"type" validate:"required,oneof=supervisord"`
-	Operate string `json:"operate" validate:"oneof=status restart start stop"`
+	Operate string `json:"operate" validate:"oneof=status restart start stop enable disable uninstall"`
+	// Manager optionally forces the operation to run through a specific
+	// registered service manager (e.g. "systemd") instead of the default one.
+	Manager string `json:"manager"`
+}
+
+type ServiceNameBatchReq struct {
+	Keywords []string `json:"keywords" validate:"required"`
+}
+
+type ServiceDiscoveryReq struct {
+	Keyword string `json:"keyword"`
+	// Limit caps the number of results returned; 0 (the default) is
+	// unbounded.
+	Limit int `json:"limit"`
+	// Offset skips this many results from the start of the sorted list,
+	// for paging through a large result set.
+	Offset int `json:"offset"`
+}
+
+type ServiceNamePinReq struct {
+	Keyword     string `json:"keyword" validate:"required"`
+	ServiceName string `json:"serviceName" validate:"required"`
+}
+
+// ServiceAliasReq adds extra candidate service/unit names to try for
+// Keyword, alongside the hard-coded predefined ones, for a service that
+// registers under a distro-specific unit name (e.g. "clamd@scan" instead
+// of "clamav").
+type ServiceAliasReq struct {
+	Keyword string   `json:"keyword" validate:"required"`
+	Names   []string `json:"names" validate:"required"`
+}
+
+type ServiceCustomCommandReq struct {
+	ServiceName string `json:"serviceName" validate:"required"`
+	Action      string `json:"action" validate:"required"`
+	Command     string `json:"command" validate:"required"`
 }
 
 type HostToolCreate struct {
@@ -13,6 +50,22 @@ type HostToolCreate struct {
 type SupervisorConfig struct {
 	ConfigPath  string `json:"configPath"`
 	ServiceName string `json:"serviceName"`
+	// EnableOnBoot controls whether the managed supervisor service is
+	// enabled to start on boot once it's created, in addition to being
+	// restarted immediately.
+	EnableOnBoot bool `json:"enableOnBoot"`
+	// Coexist must be set to proceed when the resolved supervisord.conf
+	// already has a non-empty include.files glob we didn't set (e.g. a
+	// distro-managed /etc/supervisor/conf.d setup). It adds our own
+	// include glob alongside the existing one instead of replacing it, so
+	// both stay active. Without it, CreateToolConfig refuses to touch a
+	// config it doesn't already own.
+	Coexist bool `json:"coexist"`
+	// LogDir and IncludeDir move supervisor's managed process logs and
+	// per-process ini includes off BaseDir (e.g. onto external storage).
+	// Leave blank to use BaseDir/1panel/tools/supervisord/{log,supervisor.d}.
+	LogDir     string `json:"logDir"`
+	IncludeDir string `json:"includeDir"`
 }
 
 type HostToolLogReq struct {
@@ -20,9 +73,28 @@ type HostToolLogReq struct {
 }
 
 type HostToolConfig struct {
-	Type    string `json:"type" validate:"required,oneof=supervisord"`
-	Operate string `json:"operate" validate:"oneof=get set"`
+	Type string `json:"type" validate:"required,oneof=supervisord"`
+	// preview returns a unified diff of Content against the file on disk
+	// without writing it, so the UI can show what a subsequent set would
+	// change before committing to it. repair re-applies our managed
+	// include directive if it's drifted away (see response.Supervisor's
+	// ConfigDrift), ignoring Content entirely.
+	Operate string `json:"operate" validate:"oneof=get set get-settings set-settings preview repair"`
 	Content string `json:"content"`
+	// Settings carries the structured [supervisord] section fields for the
+	// set-settings operate type; it's ignored for get/set/get-settings.
+	Settings SupervisordSettings `json:"settings"`
+}
+
+// SupervisordSettings mirrors a subset of the [supervisord] section's keys
+// that are safe to edit structurally, so users don't have to hand-edit ini
+// text and risk a typo that breaks the next restart.
+type SupervisordSettings struct {
+	Logfile  string `json:"logfile"`
+	Loglevel string `json:"loglevel"`
+	Pidfile  string `json:"pidfile"`
+	Minfds   string `json:"minfds"`
+	Minprocs string `json:"minprocs"`
 }
 
 type SupervisorProcessConfig struct {
@@ -30,12 +102,114 @@ type SupervisorProcessConfig struct {
 	Operate  string `json:"operate"`
 	Command  string `json:"command"`
 	User     string `json:"user"`
+	// Dir is the program's working directory. Optional: some commands
+	// (absolute paths, scripts that cd themselves) don't need one, so an
+	// empty Dir omits supervisord's "directory" key instead of failing.
 	Dir      string `json:"dir"`
 	Numprocs string `json:"numprocs"`
+	// Priority controls supervisord's start/shutdown ordering across
+	// programs (lower starts first, shuts down last). Defaults to 999
+	// when nil, matching supervisord's own default.
+	Priority *int `json:"priority"`
+	// Group, when set, adds this program to a supervisord [group:<Group>]
+	// section alongside any other program sharing the same group, so
+	// they can be started/stopped/restarted together.
+	Group string `json:"group"`
+	// ImportContent holds the raw contents of a hand-written `program:`
+	// ini file, used only when Operate is "import".
+	ImportContent string `json:"importContent"`
+	// PreserveLogPaths keeps an imported config's stdout/stderr log
+	// paths as written, instead of rewriting them into our managed
+	// log/ directory.
+	PreserveLogPaths bool `json:"preserveLogPaths"`
+	// Force applies to Operate "stop" and "delete": if the process doesn't
+	// stop gracefully, it's sent SIGKILL via `supervisorctl signal KILL`
+	// instead of leaving the caller to wait out stopwaitsecs.
+	Force bool `json:"force"`
+}
+// SupervisorEventListenerConfig creates or deletes a supervisord
+// `[eventlistener:<name>]` section, used for plugins like memmon that watch
+// process events (e.g. crashes, memory thresholds) rather than running a
+// long-lived program of their own.
+type SupervisorEventListenerConfig struct {
+	Name    string `json:"name" validate:"required"`
+	Operate string `json:"operate" validate:"required,oneof=create delete"`
+	Command string `json:"command"`
+	// Events is supervisord's comma-separated event type list, e.g.
+	// "PROCESS_STATE,TICK_60". Required for create.
+	Events string `json:"events"`
+	// BufferSize caps the number of events supervisord queues for this
+	// listener; empty defaults to supervisord's own default of 10.
+	BufferSize string `json:"bufferSize"`
 }
+
+type SupervisorConfigsBackupReq struct {
+	// WithLogs also bundles each program's stdout/stderr log files
+	// alongside its .ini, not just the process definitions.
+	WithLogs bool `json:"withLogs"`
+}
+
+type SupervisorConfigsRestoreReq struct {
+	// TarPath is the path of a previously-uploaded tar.gz produced by the
+	// export operation (or matching its layout: one .ini per program at
+	// the archive root).
+	TarPath string `json:"tarPath" validate:"required"`
+}
+
+// SupervisorInetConfigReq enables (or reconfigures) supervisord's XML-RPC
+// control interface by writing the [inet_http_server] and [supervisorctl]
+// sections of supervisord.conf.
+type SupervisorInetConfigReq struct {
+	// BindAddress is a host:port pair, e.g. "127.0.0.1:9001". Binding to
+	// 0.0.0.0 exposes control of every managed process without a firewall
+	// rule in front of it, so callers should prefer a loopback or internal
+	// address.
+	BindAddress string `json:"bindAddress" validate:"required"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+}
+
+// SupervisorBinariesReq configures non-PATH install locations for
+// supervisord/supervisorctl (e.g. a Python venv or /opt). Either field left
+// empty falls back to a plain PATH lookup of the bare binary name.
+type SupervisorBinariesReq struct {
+	SupervisordBinary   string `json:"supervisordBinary"`
+	SupervisorctlBinary string `json:"supervisorctlBinary"`
+}
+
+// SupervisorReloadReq drives the two daemon-wide operate types distinct
+// from any single process: "reread" previews what a subsequent "reload"
+// would change without applying it, and "reload" is a full `supervisorctl
+// reload` (reread every config and restart whatever changed), for a UI
+// "reload all configs" action rather than update's reread+update dance.
+type SupervisorReloadReq struct {
+	Operate string `json:"operate" validate:"required,oneof=reread reload"`
+}
+
+// SupervisorProcessTestReq runs a candidate program command for a bounded
+// time without writing an ini, so a wrong command (the usual cause of a
+// FATAL-state process) surfaces immediately instead of only after
+// OperateSupervisorProcess's create/update and a trip to the program's log.
+type SupervisorProcessTestReq struct {
+	Command string `json:"command" validate:"required"`
+	User    string `json:"user" validate:"required"`
+	Dir     string `json:"dir"`
+}
+
 type SupervisorProcessFileReq struct {
 	Name    string `json:"name" validate:"required"`
-	Operate string `json:"operate" validate:"required,oneof=get clear update" `
+	Operate string `json:"operate" validate:"required,oneof=get clear update validate" `
 	Content string `json:"content"`
 	File    string `json:"file" validate:"required,oneof=out.log err.log config"`
+	ConfigOption
+}
+
+// ConfigOption controls how a file's content is returned for viewing: the
+// whole file by default, or a line window via HeadLines/TailLines (HeadLines
+// wins if both are set), optionally filtered further to lines matching the
+// Grep regular expression.
+type ConfigOption struct {
+	HeadLines int    `json:"headLines"`
+	TailLines int    `json:"tailLines"`
+	Grep      string `json:"grep"`
 }
@@ -1,5 +1,7 @@
 package response
 
+import "github.com/1Panel-dev/1Panel/backend/app/dto/request"
+
 type HostToolRes struct {
 	Type   string      `json:"type"`
 	Config interface{} `json:"config"`
@@ -16,10 +18,40 @@ type Supervisor struct {
 	Status      string `json:"status"`
 	CtlExist    bool   `json:"ctlExist"`
 	ServiceName string `json:"serviceName"`
+	Enabled     bool   `json:"enabled"`
+	// ConfigDrift is true when supervisord.conf's include.files glob no
+	// longer references our managed include directory (e.g. an operator
+	// hand-edited the file), which means supervisord has silently stopped
+	// loading the processes GetSupervisorProcessConfig still lists. Repair
+	// it with OperateToolConfig's "repair" operate type.
+	ConfigDrift bool `json:"configDrift"`
+}
+
+// ToolOperateRes reports the resulting enabled-at-boot state after an
+// OperateTool "enable"/"disable" call, so the UI can update its toggle
+// without a separate GetToolStatus round trip.
+type ToolOperateRes struct {
+	Enabled bool `json:"enabled"`
 }
 
 type HostToolConfig struct {
-	Content string `json:"content"`
+	Content  string                      `json:"content"`
+	Settings request.SupervisordSettings `json:"settings"`
+	// Diff is a unified diff of old vs new content, populated for the
+	// "preview" operate type (and logged, not returned, for "set").
+	Diff string `json:"diff"`
+}
+
+// ToolLogInfo reports a host tool's own log (not a managed process's log):
+// where it's resolved to, how it's reached (a readable file vs. syslog),
+// and its configured rotation settings. Content is only populated when
+// Source is "file".
+type ToolLogInfo struct {
+	Content  string `json:"content"`
+	LogPath  string `json:"logPath"`
+	Source   string `json:"source"`
+	MaxBytes int64  `json:"maxBytes"`
+	Backups  int    `json:"backups"`
 }
 
 type SupervisorProcessConfig struct {
@@ -28,8 +60,91 @@ type SupervisorProcessConfig struct {
 	User     string          `json:"user"`
 	Dir      string          `json:"dir"`
 	Numprocs string          `json:"numprocs"`
+	Priority int             `json:"priority"`
+	Group    string          `json:"group"`
 	Msg      string          `json:"msg"`
 	Status   []ProcessStatus `json:"status"`
+	LogFiles []LogFileStatus `json:"logFiles"`
+}
+
+// LogFileStatus reports the current size of a supervisor-managed program's
+// log file against its configured rotation cap, so operators can spot logs
+// that are about to fill the disk.
+type LogFileStatus struct {
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	MaxBytes int64  `json:"maxBytes"`
+	Backups  int    `json:"backups"`
+	NearCap  bool   `json:"nearCap"`
+}
+
+// SupervisorEventListenerConfig reports an `[eventlistener:<name>]` section
+// alongside the regular `[program:<name>]` ones returned by
+// GetSupervisorProcessConfig, since a listener isn't itself a process entry
+// the frontend should try to start/stop/restart.
+type SupervisorEventListenerConfig struct {
+	Name       string `json:"name"`
+	Command    string `json:"command"`
+	Events     string `json:"events"`
+	BufferSize string `json:"bufferSize"`
+}
+
+// SupervisorConfigValidation is the result of validating a hand-edited
+// program config before it's written to disk, so a bad edit is caught
+// immediately instead of surfacing only once `supervisorctl update` runs
+// against the bad file.
+type SupervisorConfigValidation struct {
+	Valid  bool                             `json:"valid"`
+	Errors []SupervisorConfigValidationItem `json:"errors"`
+}
+
+// SupervisorConfigValidationItem pinpoints one problem found in a submitted
+// config by section and (when found) source line, so the UI can point a
+// user straight at what to fix.
+type SupervisorConfigValidationItem struct {
+	Section string `json:"section"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// SupervisorProcessTemplate is a predefined starting point for a supervisor
+// process config, covering the fields that differ by runtime (command) so
+// operators don't have to write them from scratch for common cases.
+type SupervisorProcessTemplate struct {
+	Name     string `json:"name"`
+	Command  string `json:"command"`
+	Numprocs string `json:"numprocs"`
+}
+
+// SupervisorReread is a structured form of `supervisorctl reread`'s output,
+// naming which programs a following "update"/"reload" would add, change, or
+// remove, so a UI can preview the effect before applying it. It's also
+// returned (always empty) after a "reload", which folds reread into the
+// same daemon-wide refresh and restarts whatever changed itself.
+type SupervisorReread struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// ServiceActiveStatus reports a service keyword's resolved active/enabled
+// state for a status card in the UI, e.g. docker's. Enabled is only
+// meaningful on hosts with a real init-system unit to ask about; for a
+// keyword resolved via a process/socket fallback (no unit at all) it just
+// mirrors Active.
+type ServiceActiveStatus struct {
+	Active  bool `json:"active"`
+	Enabled bool `json:"enabled"`
+}
+
+// SupervisorProcessTestResult is the captured outcome of a
+// SupervisorProcessTestReq run: what it printed, how it exited, and whether
+// it had to be killed for running past the bounded timeout.
+type SupervisorProcessTestResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exitCode"`
+	TimedOut bool   `json:"timedOut"`
 }
 
 type ProcessStatus struct {
@@ -38,4 +153,11 @@ type ProcessStatus struct {
 	PID    string `json:"PID"`
 	Uptime string `json:"uptime"`
 	Msg    string `json:"msg"`
+	// RSSBytes and CPUPercent are resource usage read from /proc for a
+	// RUNNING process (see enrichProcessResourceUsage); both stay zero when
+	// the process isn't running or /proc isn't available. CPUPercent is a
+	// lifetime average -- total CPU time over the process's age -- not an
+	// instantaneous rate, which would need two samples spaced apart.
+	RSSBytes   uint64  `json:"rssBytes"`
+	CPUPercent float64 `json:"cpuPercent"`
 }
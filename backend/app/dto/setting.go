@@ -138,6 +138,24 @@ type UpgradeInfo struct {
 	NewVersion    string `json:"newVersion"`
 	LatestVersion string `json:"latestVersion"`
 	ReleaseNote   string `json:"releaseNote"`
+
+	// JumpKind classifies the step from the current version to itemVersion
+	// (LatestVersion, or NewVersion when set) as "major", "minor", or
+	// "patch", so the UI can warn before a big jump. Empty when it couldn't
+	// be determined.
+	JumpKind string `json:"jumpKind"`
+	// PublishedAt is the target release's publish date, populated from the
+	// GitHub releases API for wrt builds. Zero when unavailable.
+	PublishedAt time.Time `json:"publishedAt"`
+	// SkippedVersions lists the released versions between the current
+	// version and itemVersion (exclusive of both), populated from the
+	// GitHub releases API for wrt builds.
+	SkippedVersions []string `json:"skippedVersions"`
+}
+
+type CheckUpgradeInfo struct {
+	HasUpdate     bool   `json:"hasUpdate"`
+	LatestVersion string `json:"latestVersion"`
 }
 
 type SyncTime struct {
@@ -153,6 +171,38 @@ type Upgrade struct {
 	Version string `json:"version" validate:"required"`
 }
 
+// VersionInfo is one entry in ListVersions's result: a version an operator
+// can pin Upgrade's req.Version to, beyond the automatic latest/newer
+// picked by SearchUpgrade.
+type VersionInfo struct {
+	Version string `json:"version"`
+	// PublishedAt is the release's publish date, populated for wrt builds
+	// (sourced from the GitHub releases API). Zero for normal builds, whose
+	// version index doesn't carry a publish date.
+	PublishedAt time.Time `json:"publishedAt"`
+	// Downgrade is true when Version is older than the host's currently
+	// installed version, so the UI can require a confirmation flag before
+	// letting an operator pick it in Upgrade.
+	Downgrade bool `json:"downgrade"`
+}
+
+type UpgradeProxy struct {
+	ProxyUrl string `json:"proxyUrl"`
+}
+
+// UpgradeStatus reports the in-memory upgrade state machine's current
+// phase, for polling progress during an upgrade without hammering the DB
+// the way repeatedly reading the SystemStatus setting would. LastError is
+// set when a step fails (Phase "Failed"), stays set through a following
+// "RollingBack"/"RolledBack", and is cleared when a new run starts.
+type UpgradeStatus struct {
+	Phase          string `json:"phase"`
+	Percent        int    `json:"percent"`
+	CurrentVersion string `json:"currentVersion"`
+	TargetVersion  string `json:"targetVersion"`
+	LastError      string `json:"lastError"`
+}
+
 type CleanData struct {
 	SystemClean    []CleanTree `json:"systemClean"`
 	UploadClean    []CleanTree `json:"uploadClean"`
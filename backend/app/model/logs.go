@@ -21,6 +21,20 @@ type OperationLog struct {
 	DetailEN string `gorm:"type:varchar(256)" json:"detailEN"`
 }
 
+// ServiceActionLog audits a single start/stop/restart/enable/disable,
+// upgrade or rollback action taken against a managed service. Unlike
+// OperationLog (which records every write API call generically), this is
+// scoped to service lifecycle actions so admins can see a focused history
+// of what happened to a given service.
+type ServiceActionLog struct {
+	BaseModel
+	Operator    string `gorm:"type:varchar(64)" json:"operator"`
+	ServiceName string `gorm:"type:varchar(128)" json:"serviceName"`
+	Action      string `gorm:"type:varchar(64)" json:"action"`
+	Result      string `gorm:"type:varchar(64)" json:"result"`
+	OutputTail  string `gorm:"type:varchar(1024)" json:"outputTail"`
+}
+
 type LoginLog struct {
 	BaseModel
 	IP      string `gorm:"type:varchar(64)" json:"ip"`
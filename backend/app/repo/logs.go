@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"errors"
+
 	"github.com/1Panel-dev/1Panel/backend/app/model"
 	"github.com/1Panel-dev/1Panel/backend/global"
 	"gorm.io/gorm"
@@ -20,6 +22,10 @@ type ILogRepo interface {
 	CleanOperation() error
 	CreateOperationLog(user *model.OperationLog) error
 	PageOperationLog(limit, offset int, opts ...DBOption) (int64, []model.OperationLog, error)
+
+	CreateServiceActionLog(log *model.ServiceActionLog) error
+	PageServiceActionLog(limit, offset int, opts ...DBOption) (int64, []model.ServiceActionLog, error)
+	PruneServiceActionLogs(keep int) error
 }
 
 func NewILogRepo() ILogRepo {
@@ -66,6 +72,36 @@ func (u *LogRepo) PageOperationLog(page, size int, opts ...DBOption) (int64, []m
 	return count, ops, err
 }
 
+func (u *LogRepo) CreateServiceActionLog(log *model.ServiceActionLog) error {
+	return global.DB.Create(log).Error
+}
+
+func (u *LogRepo) PageServiceActionLog(page, size int, opts ...DBOption) (int64, []model.ServiceActionLog, error) {
+	var ops []model.ServiceActionLog
+	db := global.DB.Model(&model.ServiceActionLog{})
+	for _, opt := range opts {
+		db = opt(db)
+	}
+	count := int64(0)
+	db = db.Count(&count)
+	err := db.Order("created_at desc").Limit(size).Offset(size * (page - 1)).Find(&ops).Error
+	return count, ops, err
+}
+
+// PruneServiceActionLogs deletes every service action log older than the
+// keep-th most recent one, so the table doesn't grow without bound.
+func (u *LogRepo) PruneServiceActionLogs(keep int) error {
+	var cutoff model.ServiceActionLog
+	err := global.DB.Model(&model.ServiceActionLog{}).Order("created_at desc").Offset(keep).Limit(1).First(&cutoff).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return global.DB.Where("created_at <= ?", cutoff.CreatedAt).Delete(&model.ServiceActionLog{}).Error
+}
+
 func (c *LogRepo) WithByStatus(status string) DBOption {
 	return func(g *gorm.DB) *gorm.DB {
 		if len(status) == 0 {
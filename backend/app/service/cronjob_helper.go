@@ -1,9 +1,11 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
@@ -203,18 +205,30 @@ func handleTar(sourceDir, targetDir, name, exclusionRules string) error {
 }
 
 func handleUnTar(sourceFile, targetDir string) error {
+	return handleUnTarWithContext(context.Background(), sourceFile, targetDir)
+}
+
+// handleUnTarWithContext is handleUnTar with ctx bound to the extraction
+// process, so a caller that needs to abort a long extract (the upgrade
+// flow's cancellation) can kill `tar` instead of waiting it out.
+func handleUnTarWithContext(ctx context.Context, sourceFile, targetDir string) error {
 	if _, err := os.Stat(targetDir); err != nil && os.IsNotExist(err) {
 		if err = os.MkdirAll(targetDir, os.ModePerm); err != nil {
 			return err
 		}
 	}
 
-	commands := fmt.Sprintf("tar -zxvf %s -C %s", sourceFile, targetDir)
-	global.LOG.Debug(commands)
-	stdout, err := cmd.ExecWithTimeOut(commands, 24*time.Hour)
-	if err != nil {
-		global.LOG.Errorf("do handle untar failed, stdout: %s, err: %v", stdout, err)
-		return errors.New(stdout)
+	global.LOG.Debugf("tar -zxvf %s -C %s", sourceFile, targetDir)
+	var stdout bytes.Buffer
+	tarCmd := exec.CommandContext(ctx, "tar", "-zxvf", sourceFile, "-C", targetDir)
+	tarCmd.Stdout = &stdout
+	tarCmd.Stderr = &stdout
+	if err := tarCmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		global.LOG.Errorf("do handle untar failed, stdout: %s, err: %v", stdout.String(), err)
+		return errors.New(stdout.String())
 	}
 	return nil
 }
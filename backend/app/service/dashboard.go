@@ -11,6 +11,7 @@ import (
 	"github.com/1Panel-dev/1Panel/backend/app/dto"
 	"github.com/1Panel-dev/1Panel/backend/global"
 	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+	"github.com/1Panel-dev/1Panel/backend/utils/systemctl"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
@@ -68,6 +69,9 @@ func (u *DashboardService) LoadOsInfo() (*dto.OsInfo, error) {
 	if baseInfo.KernelArch == "x86_64" {
 		baseInfo.KernelArch = "amd64"
 	}
+	if managerName, available := systemctl.ActiveManager(); available {
+		baseInfo.ServiceManager = managerName
+	}
 	return &baseInfo, nil
 }
 
@@ -0,0 +1,17 @@
+//go:build linux
+
+package service
+
+import "syscall"
+
+// availableDiskSpace returns the free bytes on the filesystem containing
+// path. ok is false when the free-space check isn't supported on this
+// platform, so callers can skip the preflight instead of failing upgrades
+// outright.
+func availableDiskSpace(path string) (free uint64, ok bool, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, true, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), true, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package service
+
+// availableDiskSpace reports that the free-space check isn't supported on
+// this platform, so the disk-space preflight skips gracefully instead of
+// failing upgrades outright.
+func availableDiskSpace(path string) (free uint64, ok bool, err error) {
+	return 0, false, nil
+}
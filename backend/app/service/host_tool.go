@@ -2,36 +2,79 @@ package service
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/1Panel-dev/1Panel/backend/app/dto/request"
 	"github.com/1Panel-dev/1Panel/backend/app/dto/response"
 	"github.com/1Panel-dev/1Panel/backend/buserr"
 	"github.com/1Panel-dev/1Panel/backend/constant"
 	"github.com/1Panel-dev/1Panel/backend/global"
+	"github.com/1Panel-dev/1Panel/backend/i18n"
 	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
 	"github.com/1Panel-dev/1Panel/backend/utils/files"
 	"github.com/1Panel-dev/1Panel/backend/utils/ini_conf"
+	"github.com/1Panel-dev/1Panel/backend/utils/servicectl"
+	"github.com/1Panel-dev/1Panel/backend/utils/supervisorrpc"
 	"github.com/1Panel-dev/1Panel/backend/utils/systemctl"
+	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
 	"gopkg.in/ini.v1"
+	"io"
+	"net"
+	"os"
 	"os/exec"
 	"os/user"
 	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
+// nearCapRatio is the fraction of a log file's configured max size at which
+// it is flagged as close to rotating, so operators can catch logs that are
+// about to fill the disk before they actually do.
+const nearCapRatio = 0.9
+
 type HostToolService struct{}
 
 type IHostToolService interface {
 	GetToolStatus(req request.HostToolReq) (*response.HostToolRes, error)
 	CreateToolConfig(req request.HostToolCreate) error
-	OperateTool(req request.HostToolReq) error
+	OperateTool(req request.HostToolReq, operator string) (response.ToolOperateRes, error)
 	OperateToolConfig(req request.HostToolConfig) (*response.HostToolConfig, error)
-	GetToolLog(req request.HostToolLogReq) (string, error)
+	SetSupervisorInetConfig(req request.SupervisorInetConfigReq) error
+	SetSupervisorBinaries(req request.SupervisorBinariesReq) error
+	GetToolLog(req request.HostToolLogReq) (response.ToolLogInfo, error)
 	OperateSupervisorProcess(req request.SupervisorProcessConfig) error
 	GetSupervisorProcessConfig() ([]response.SupervisorProcessConfig, error)
+	OperateSupervisorEventListener(req request.SupervisorEventListenerConfig) error
+	GetSupervisorEventListeners() ([]response.SupervisorEventListenerConfig, error)
 	OperateSupervisorProcessFile(req request.SupervisorProcessFileReq) (string, error)
+	SetServiceCustomCommand(req request.ServiceCustomCommandReq) error
+	GetSupervisorProcessTemplates() []response.SupervisorProcessTemplate
+	GetSupervisorProcessLogPath(req request.SupervisorProcessFileReq) (string, error)
+	ValidateSupervisorProcessConfig(req request.SupervisorProcessFileReq) (response.SupervisorConfigValidation, error)
+	FollowSupervisorProcessLog(wsConn *websocket.Conn, req request.SupervisorProcessFileReq) error
+	GetServiceNames(keywords []string) map[string]string
+	GetServiceStatus(keyword string) (response.ServiceActiveStatus, error)
+	PinServiceName(req request.ServiceNamePinReq) error
+	UnpinServiceName(keyword string) error
+	AddServiceAlias(req request.ServiceAliasReq) error
+	GetInitSystems() []systemctl.ManagerInfo
+	ExportSupervisorProcessConfigs(req request.SupervisorConfigsBackupReq) (string, error)
+	ImportSupervisorProcessConfigs(req request.SupervisorConfigsRestoreReq) error
+	FindServices(req request.ServiceDiscoveryReq) ([]string, error)
+	OperateSupervisorReload(req request.SupervisorReloadReq) (*response.SupervisorReread, error)
+	TestSupervisorProcessCommand(req request.SupervisorProcessTestReq) (*response.SupervisorProcessTestResult, error)
 }
 
 func NewIHostToolService() IHostToolService {
@@ -44,7 +87,7 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 	switch req.Type {
 	case constant.Supervisord:
 		supervisorConfig := &response.Supervisor{}
-		if !cmd.Which(constant.Supervisord) {
+		if !cmd.Which(resolveSupervisordBinary()) {
 			supervisorConfig.IsExist = false
 			res.Config = supervisorConfig
 			return res, nil
@@ -69,9 +112,9 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 			supervisorConfig.ServiceName = serviceNameSet.Value
 		}
 
-		versionRes, _ := cmd.Exec("supervisord -v")
+		versionRes, _ := cmd.Exec(resolveSupervisordBinary() + " -v")
 		supervisorConfig.Version = strings.TrimSuffix(versionRes, "\n")
-		_, ctlRrr := exec.LookPath("supervisorctl")
+		_, ctlRrr := exec.LookPath(resolveSupervisorctlBinary())
 		supervisorConfig.CtlExist = ctlRrr == nil
 
 		active, _ := systemctl.IsActive(supervisorConfig.ServiceName)
@@ -80,10 +123,15 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 		} else {
 			supervisorConfig.Status = "stopped"
 		}
+		supervisorConfig.Enabled, _ = systemctl.IsEnable(supervisorConfig.ServiceName)
+		if conflict, _ := hasConflictingSupervisord(); conflict {
+			supervisorConfig.Msg = i18n.GetMsgByKey("ErrSupervisordConflict")
+		}
 
 		pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
 		if pathSet.ID != 0 || pathSet.Value != "" {
 			supervisorConfig.ConfigPath = pathSet.Value
+			supervisorConfig.ConfigDrift = supervisorConfigDrifted(pathSet.Value)
 			res.Config = supervisorConfig
 			return res, nil
 		} else {
@@ -95,6 +143,18 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 		if !fileOp.Stat(servicePath) {
 			servicePath = "/usr/lib/systemd/system/supervisord.service"
 		}
+		if !fileOp.Stat(servicePath) {
+			// Neither of the two conventional paths exist as shipped; the
+			// unit may have been installed elsewhere (e.g. /etc/systemd/system
+			// for a distro override) or as a template instance, so fall
+			// back to a full search before giving up.
+			for _, candidate := range []string{"supervisor.service", "supervisord.service"} {
+				if found, ok := servicectl.GetServicePath(candidate); ok {
+					servicePath = found.UnitPath
+					break
+				}
+			}
+		}
 		if fileOp.Stat(servicePath) {
 			startCmd, _ := ini_conf.GetIniValue(servicePath, "Service", "ExecStart")
 			if startCmd != "" {
@@ -111,6 +171,18 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 				}
 			}
 		}
+		if supervisorConfig.ConfigPath == "" {
+			for _, name := range []string{"supervisor", "supervisord"} {
+				scriptPath, ok := servicectl.GetInitScriptPath(name)
+				if !ok {
+					continue
+				}
+				if found := resolveSupervisorConfigPathFromInitScript(scriptPath); found != "" {
+					supervisorConfig.ConfigPath = found
+					break
+				}
+			}
+		}
 		if supervisorConfig.ConfigPath == "" {
 			configPath := "/etc/supervisord.conf"
 			if !fileOp.Stat(configPath) {
@@ -126,6 +198,47 @@ func (h *HostToolService) GetToolStatus(req request.HostToolReq) (*response.Host
 	return res, nil
 }
 
+// initScriptConfigFlagPattern matches a `-c <path>` flag in an openrc,
+// sysvinit or procd init script's supervisord invocation, the shell-script
+// equivalent of the `-c` flag GetToolStatus pulls out of a systemd unit's
+// ExecStart.
+var initScriptConfigFlagPattern = regexp.MustCompile(`-c\s+"?([^"\s]+)"?`)
+
+// resolveSupervisorConfigPathFromInitScript greps an openrc/sysvinit/procd
+// init script for the `-c <path>` flag its supervisord invocation passes.
+// Unlike a systemd unit, these scripts have no [Service] section for
+// ini_conf.GetIniValue to read ExecStart from -- the command line is
+// embedded directly in shell, so the config path has to be grepped out of
+// the script's own text instead.
+func resolveSupervisorConfigPathFromInitScript(scriptPath string) string {
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return ""
+	}
+	match := initScriptConfigFlagPattern.FindSubmatch(content)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// hasConflictingSupervisord reports whether more than one supervisord
+// process is running on the host. More than one usually means a
+// system-installed instance outside of BaseDir is fighting with the one
+// 1Panel manages for control of the same programs.
+func hasConflictingSupervisord() (bool, error) {
+	out, err := cmd.Exec("pgrep -x supervisord")
+	if err != nil {
+		return false, nil
+	}
+	return countSupervisordPIDs(out) > 1, nil
+}
+
+// countSupervisordPIDs counts the PIDs in pgrep's output, one per line.
+func countSupervisordPIDs(pgrepOutput string) int {
+	return len(strings.Fields(strings.TrimSpace(pgrepOutput)))
+}
+
 func (h *HostToolService) CreateToolConfig(req request.HostToolCreate) error {
 	switch req.Type {
 	case constant.Supervisord:
@@ -145,61 +258,289 @@ func (h *HostToolService) CreateToolConfig(req request.HostToolCreate) error {
 		if err != nil {
 			return err
 		}
-		if targetKey != nil {
-			_, err = service.NewKey(";files", targetKey.Value())
-			if err != nil {
-				return err
-			}
+		if err = upsertSetting(constant.SupervisorIncludeDir, req.IncludeDir); err != nil {
+			return err
 		}
-		supervisorDir := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord")
-		includeDir := path.Join(supervisorDir, "supervisor.d")
-		if !fileOp.Stat(includeDir) {
-			if err = fileOp.CreateDir(includeDir, 0755); err != nil {
-				return err
-			}
+		if err = upsertSetting(constant.SupervisorLogDir, req.LogDir); err != nil {
+			return err
 		}
-		logDir := path.Join(supervisorDir, "log")
-		if !fileOp.Stat(logDir) {
-			if err = fileOp.CreateDir(logDir, 0755); err != nil {
-				return err
+		includeDir := resolveManagedSupervisorIncludeDir()
+		includePath := path.Join(includeDir, "*.ini")
+		if externallyManaged := targetKey.Value() != "" && !supervisorIncludeIsOurs(targetKey.Value(), includePath); externallyManaged {
+			if !req.Coexist {
+				return buserr.New("ErrSupervisorConfigExternallyManaged")
 			}
+			targetKey.SetValue(strings.TrimSpace(targetKey.Value() + " " + includePath))
+		} else {
+			if targetKey != nil {
+				_, err = service.NewKey(";files", targetKey.Value())
+				if err != nil {
+					return err
+				}
+			}
+			targetKey.SetValue(includePath)
+		}
+		if err = ensureDirWritable(fileOp, includeDir); err != nil {
+			return err
+		}
+		logDir := resolveSupervisorLogDir()
+		if err = ensureDirWritable(fileOp, logDir); err != nil {
+			return err
 		}
-		includePath := path.Join(includeDir, "*.ini")
-		targetKey.SetValue(includePath)
 		if err = cfg.SaveTo(req.ConfigPath); err != nil {
 			return err
 		}
 
-		serviceNameSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorServiceName))
-		if serviceNameSet.ID != 0 {
-			if err = settingRepo.Update(constant.SupervisorServiceName, req.ServiceName); err != nil {
-				return err
-			}
-		} else {
-			if err = settingRepo.Create(constant.SupervisorServiceName, req.ServiceName); err != nil {
+		if err = upsertSetting(constant.SupervisorServiceName, req.ServiceName); err != nil {
+			return err
+		}
+		if err = upsertSetting(constant.SupervisorConfigPath, req.ConfigPath); err != nil {
+			return err
+		}
+		if err = systemctl.Restart(req.ServiceName); err != nil {
+			global.LOG.Errorf("[init] restart %s failed err %s", req.ServiceName, err.Error())
+			return err
+		}
+		enableAction := "disable"
+		if req.EnableOnBoot {
+			enableAction = "enable"
+			if err = verifyServiceCanBeEnabled(req.ServiceName); err != nil {
 				return err
 			}
 		}
+		if err = systemctl.Operate(enableAction, req.ServiceName); err != nil {
+			global.LOG.Errorf("[init] %s %s failed err %s", enableAction, req.ServiceName, err.Error())
+			return err
+		}
+		systemctl.FlushDiscoveryCache()
+	}
+	return nil
+}
 
-		configPathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
-		if configPathSet.ID != 0 {
-			if err = settingRepo.Update(constant.SupervisorConfigPath, req.ConfigPath); err != nil {
-				return err
-			}
-		} else {
-			if err = settingRepo.Create(constant.SupervisorConfigPath, req.ConfigPath); err != nil {
-				return err
-			}
+// unifiedConfigDiff renders a unified diff of a config file's old and new
+// content, labeled with path, so OperateToolConfig's set/preview operate
+// types can show exactly what a write would change.
+func unifiedConfigDiff(oldContent, newContent, path string) (string, error) {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(oldContent),
+		B:        difflib.SplitLines(newContent),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	}
+	return difflib.GetUnifiedDiffString(diff)
+}
+
+// upsertSetting creates the setting if it doesn't exist yet, otherwise
+// updates it in place.
+func upsertSetting(key, value string) error {
+	set, _ := settingRepo.Get(settingRepo.WithByKey(key))
+	if set.ID != 0 {
+		return settingRepo.Update(key, value)
+	}
+	return settingRepo.Create(key, value)
+}
+
+// ensureDirWritable creates dir if it doesn't exist and confirms the
+// process can actually write into it, by writing and removing a throwaway
+// probe file. Settings-derived dirs can point anywhere on the filesystem,
+// unlike BaseDir which 1Panel already controls, so this is checked eagerly
+// instead of surfacing as an opaque failure later when supervisord itself
+// tries to write there.
+func ensureDirWritable(fileOp files.FileOp, dir string) error {
+	if !fileOp.Stat(dir) {
+		if err := fileOp.CreateDir(dir, 0755); err != nil {
+			return err
 		}
-		if err = systemctl.Restart(req.ServiceName); err != nil {
-			global.LOG.Errorf("[init] restart %s failed err %s", req.ServiceName, err.Error())
+	}
+	probe := path.Join(dir, ".1panel_write_test")
+	if err := fileOp.SaveFile(probe, "", 0644); err != nil {
+		return buserr.WithName("ErrDirNotWritable", dir)
+	}
+	_ = fileOp.DeleteFile(probe)
+	return nil
+}
+
+// verifyServiceCanBeEnabled checks that serviceName has a unit systemd knows
+// about before an enable is attempted against it, so a missing unit surfaces
+// as a clear, localized error instead of systemctl's raw output.
+func verifyServiceCanBeEnabled(serviceName string) error {
+	exist, err := systemctl.IsExist(serviceName)
+	if err != nil || !exist {
+		return buserr.WithMap("ErrServiceCannotBeEnabled", map[string]interface{}{"name": serviceName}, err)
+	}
+	return nil
+}
+
+// supervisorIncludeIsOurs reports whether an include section's existing
+// "files" glob(s) already contain ourIncludePath, so CreateToolConfig can
+// tell a conf we already manage (including one we coexist-merged into on a
+// prior run) apart from one still serving only someone else's includes.
+func supervisorIncludeIsOurs(existingFiles, ourIncludePath string) bool {
+	for _, f := range strings.Fields(existingFiles) {
+		if f == ourIncludePath {
+			return true
+		}
+	}
+	return false
+}
+
+// supervisorConfigDrifted reports whether configPath's include.files glob no
+// longer references our managed include directory. This happens if an
+// operator hand-edits supervisord.conf and drops (or never restores) the
+// include CreateToolConfig set up: supervisord silently stops loading our
+// managed processes even though GetSupervisorProcessConfig keeps listing
+// them from the include directory on disk.
+func supervisorConfigDrifted(configPath string) bool {
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return true
+	}
+	section, err := cfg.GetSection("include")
+	if err != nil {
+		return true
+	}
+	filesKey, err := section.GetKey("files")
+	if err != nil {
+		return true
+	}
+	includePath := path.Join(resolveManagedSupervisorIncludeDir(), "*.ini")
+	return !supervisorIncludeIsOurs(filesKey.Value(), includePath)
+}
+
+// repairSupervisorInclude re-applies our managed include glob to cfg's
+// [include] files key when it's missing or has drifted away from it,
+// preserving any other globs already present (the same coexist-safe merge
+// CreateToolConfig uses) instead of clobbering an operator's own includes.
+func repairSupervisorInclude(cfg *ini.File) error {
+	includePath := path.Join(resolveManagedSupervisorIncludeDir(), "*.ini")
+	section, err := cfg.GetSection("include")
+	if err != nil {
+		section, err = cfg.NewSection("include")
+		if err != nil {
+			return err
+		}
+	}
+	filesKey, err := section.GetKey("files")
+	if err != nil {
+		_, err = section.NewKey("files", includePath)
+		return err
+	}
+	if supervisorIncludeIsOurs(filesKey.Value(), includePath) {
+		return nil
+	}
+	if filesKey.Value() == "" {
+		filesKey.SetValue(includePath)
+		return nil
+	}
+	filesKey.SetValue(strings.TrimSpace(filesKey.Value() + " " + includePath))
+	return nil
+}
+
+// restoreSupervisorInclude undoes whichever of CreateToolConfig's two
+// include.files strategies was used: if the value still exactly matches
+// what we overwrote it to, it's restored from the ";files" backup key;
+// otherwise (coexist mode, where we appended our glob alongside an
+// operator's existing one(s) rather than replacing them) our glob is
+// dropped from the space-separated list and the rest is left active. If an
+// operator has since repointed the config elsewhere, it's left alone rather
+// than silently overwritten.
+func restoreSupervisorInclude(cfg *ini.File, expectedIncludePath string) (bool, error) {
+	section, err := cfg.GetSection("include")
+	if err != nil {
+		return false, err
+	}
+	filesKey, err := section.GetKey("files")
+	if err != nil {
+		return false, err
+	}
+	if filesKey.Value() == expectedIncludePath {
+		backupKey, err := section.GetKey(";files")
+		if err != nil {
+			return false, nil
+		}
+		filesKey.SetValue(backupKey.Value())
+		section.DeleteKey(";files")
+		return true, nil
+	}
+
+	fields := strings.Fields(filesKey.Value())
+	kept := fields[:0]
+	found := false
+	for _, f := range fields {
+		if f == expectedIncludePath {
+			found = true
+			continue
+		}
+		kept = append(kept, f)
+	}
+	if !found {
+		return false, nil
+	}
+	filesKey.SetValue(strings.Join(kept, " "))
+	return true, nil
+}
+
+// uninstallSupervisor tears down what CreateToolConfig set up: it stops
+// every managed process, restores the original include.files value, stops
+// and disables the supervisor service itself, and clears our settings keys
+// so the host tool reports as not configured again.
+func uninstallSupervisor() error {
+	_ = operateSupervisorCtl("stop", "", "all")
+
+	configPathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
+	if configPathSet.Value != "" {
+		cfg, err := ini.Load(configPathSet.Value)
+		if err != nil {
+			return err
+		}
+		expectedIncludePath := path.Join(resolveManagedSupervisorIncludeDir(), "*.ini")
+		restored, err := restoreSupervisorInclude(cfg, expectedIncludePath)
+		if err != nil {
 			return err
 		}
+		if restored {
+			if err := cfg.SaveTo(configPathSet.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	serviceNameSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorServiceName))
+	if serviceNameSet.Value != "" {
+		_ = systemctl.Operate("disable", serviceNameSet.Value)
+		_ = systemctl.Operate("stop", serviceNameSet.Value)
 	}
+
+	_ = settingRepo.Update(constant.SupervisorServiceName, "")
+	_ = settingRepo.Update(constant.SupervisorConfigPath, "")
+	_ = settingRepo.Update(constant.SupervisorIncludeDir, "")
+	_ = settingRepo.Update(constant.SupervisorLogDir, "")
+	systemctl.FlushDiscoveryCache()
 	return nil
 }
 
-func (h *HostToolService) OperateTool(req request.HostToolReq) error {
+// supervisordInstallCommand is surfaced in ErrSupervisorNotInstalled so an
+// operator who hits it has something to copy-paste, rather than just being
+// told the binary is missing.
+const supervisordInstallCommand = "pip install supervisor"
+
+// serviceOperate performs the actual start/stop/restart/enable/disable
+// against the resolved manager; overridden in tests so OperateTool's audit
+// logging can be exercised without a live init system to drive.
+var serviceOperate = func(customCommands map[string]map[string]string, operate, serviceName, manager string) error {
+	return servicectl.GetGlobalManager(customCommands).OperateWithManager(operate, serviceName, manager)
+}
+
+func (h *HostToolService) OperateTool(req request.HostToolReq, operator string) (response.ToolOperateRes, error) {
+	if req.Type == constant.Supervisord {
+		if req.Operate == "uninstall" {
+			return response.ToolOperateRes{}, uninstallSupervisor()
+		}
+		if !cmd.Which(resolveSupervisordBinary()) {
+			return response.ToolOperateRes{}, buserr.WithMap("ErrSupervisorNotInstalled", map[string]interface{}{"installCmd": supervisordInstallCommand}, nil)
+		}
+	}
 	serviceName := req.Type
 	if req.Type == constant.Supervisord {
 		serviceNameSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorServiceName))
@@ -207,7 +548,149 @@ func (h *HostToolService) OperateTool(req request.HostToolReq) error {
 			serviceName = serviceNameSet.Value
 		}
 	}
-	return systemctl.Operate(req.Operate, serviceName)
+	customCommands, err := loadServiceCustomCommands()
+	if err != nil {
+		return response.ToolOperateRes{}, err
+	}
+	if _, hasCustomCommand := customCommands[serviceName][req.Operate]; !hasCustomCommand && req.Manager == "" {
+		if exist, _ := systemctl.IsExist(serviceName); !exist {
+			return response.ToolOperateRes{}, buserr.WithMap("ErrServiceNotExist", map[string]interface{}{"name": serviceName}, nil)
+		}
+	}
+	err = serviceOperate(customCommands, req.Operate, serviceName, req.Manager)
+	result, output := "success", ""
+	if err != nil {
+		result, output = "failure", err.Error()
+	}
+	recordServiceAction(operator, serviceName, req.Operate, result, output)
+	res := response.ToolOperateRes{}
+	if err == nil && (req.Operate == "enable" || req.Operate == "disable") {
+		res.Enabled, _ = systemctl.IsEnable(serviceName)
+	}
+	return res, err
+}
+
+// resolveSystemdServiceName is the default alias resolver: it treats the
+// keyword as the service name directly, succeeding only if systemd knows
+// about a unit with that name.
+func resolveSystemdServiceName(keyword string) (string, error) {
+	if exist, _ := systemctl.IsExist(keyword); exist {
+		return keyword, nil
+	}
+	return "", buserr.WithMap("ErrServiceNotExist", map[string]interface{}{"name": keyword}, nil)
+}
+
+// FindServices discovers systemd service units matching req.Keyword,
+// deterministically sorted (exact match first, then alphabetical) and
+// paged by req.Limit/req.Offset, so a host with hundreds of units doesn't
+// hand the UI an unbounded list to paginate client-side.
+func (h *HostToolService) FindServices(req request.ServiceDiscoveryReq) ([]string, error) {
+	return systemctl.FindServices(req.Keyword, req.Limit, req.Offset)
+}
+
+// GetServiceNames resolves a batch of service keywords to their systemd
+// unit names in one call, warming servicectl's alias cache so later
+// single-name lookups for the same keywords are free.
+func (h *HostToolService) GetServiceNames(keywords []string) map[string]string {
+	return servicectl.GetServiceNames(keywords, resolveSystemdServiceName)
+}
+
+// GetServiceStatus resolves keyword to its active/enabled state the same
+// way GetServiceNames resolves its unit name, except for keywords with a
+// registered activeFallbacks check (currently just "docker") it still
+// reports a usable status when no init-system unit exists for it at all --
+// e.g. dockerd running under OpenWRT's procd instead of systemd.
+func (h *HostToolService) GetServiceStatus(keyword string) (response.ServiceActiveStatus, error) {
+	active, enabled, err := servicectl.ResolveServiceActive(keyword, resolveSystemdServiceName, systemctl.IsActive, systemctl.IsEnable)
+	if err != nil {
+		return response.ServiceActiveStatus{}, err
+	}
+	return response.ServiceActiveStatus{Active: active, Enabled: enabled}, nil
+}
+
+// PinServiceName pins a keyword to an explicit service name, bypassing
+// discovery for ambiguous keywords like "ssh" that could otherwise resolve
+// to more than one installed unit.
+func (h *HostToolService) PinServiceName(req request.ServiceNamePinReq) error {
+	return servicectl.PinServiceName(req.Keyword, req.ServiceName, systemctl.IsExist)
+}
+
+// UnpinServiceName removes a manual pin, letting the keyword fall back to
+// discovery again.
+func (h *HostToolService) UnpinServiceName(keyword string) error {
+	return servicectl.UnpinServiceName(keyword)
+}
+
+// AddServiceAlias registers extra candidate service names to try for a
+// keyword, for services that commonly register under a distro-specific
+// unit name beyond the ones already covered by servicectl's predefined
+// list (e.g. a ClamAV build that installs as "clamd@scan").
+func (h *HostToolService) AddServiceAlias(req request.ServiceAliasReq) error {
+	return servicectl.AddPredefinedAlias(req.Keyword, req.Names, systemctl.IsExist)
+}
+
+// GetInitSystems lists every init system 1Panel supports, reporting which
+// ones are actually available on this host and which one is currently
+// active, so setup wizards can explain what's being used and let the user
+// pick an override.
+func (h *HostToolService) GetInitSystems() []systemctl.ManagerInfo {
+	return systemctl.ListManagers()
+}
+
+// SetServiceCustomCommand stores the shell command to run instead of the
+// manager-built one for a given service/action pair. The command is
+// validated up front so an unsafe value is rejected before it's persisted.
+func (h *HostToolService) SetServiceCustomCommand(req request.ServiceCustomCommandReq) error {
+	if err := servicectl.ValidateCustomCommand(req.Command); err != nil {
+		return err
+	}
+	customCommands, err := loadServiceCustomCommands()
+	if err != nil {
+		return err
+	}
+	if customCommands[req.ServiceName] == nil {
+		customCommands[req.ServiceName] = make(map[string]string)
+	}
+	customCommands[req.ServiceName][req.Action] = req.Command
+
+	raw, err := json.Marshal(customCommands)
+	if err != nil {
+		return err
+	}
+	commandSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.ServiceCustomCommands))
+	if commandSet.ID != 0 {
+		return settingRepo.Update(constant.ServiceCustomCommands, string(raw))
+	}
+	return settingRepo.Create(constant.ServiceCustomCommands, string(raw))
+}
+
+// supervisorProcessTemplates are the predefined command templates offered
+// when creating a new supervisor process, covering the most common ways to
+// run a long-lived process.
+var supervisorProcessTemplates = []response.SupervisorProcessTemplate{
+	{Name: "Python", Command: "python3 app.py", Numprocs: "1"},
+	{Name: "Node.js", Command: "node app.js", Numprocs: "1"},
+	{Name: "Java", Command: "java -jar app.jar", Numprocs: "1"},
+	{Name: "Shell", Command: "/bin/bash run.sh", Numprocs: "1"},
+	{Name: "Go", Command: "./app", Numprocs: "1"},
+}
+
+// GetSupervisorProcessTemplates returns the built-in command templates an
+// operator can start from when creating a new supervisor process.
+func (h *HostToolService) GetSupervisorProcessTemplates() []response.SupervisorProcessTemplate {
+	return supervisorProcessTemplates
+}
+
+func loadServiceCustomCommands() (map[string]map[string]string, error) {
+	customCommands := make(map[string]map[string]string)
+	commandSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.ServiceCustomCommands))
+	if commandSet.ID == 0 || commandSet.Value == "" {
+		return customCommands, nil
+	}
+	if err := json.Unmarshal([]byte(commandSet.Value), &customCommands); err != nil {
+		return nil, err
+	}
+	return customCommands, nil
 }
 
 func (h *HostToolService) OperateToolConfig(req request.HostToolConfig) (*response.HostToolConfig, error) {
@@ -233,6 +716,16 @@ func (h *HostToolService) OperateToolConfig(req request.HostToolConfig) (*respon
 			return nil, err
 		}
 		res.Content = string(content)
+	case "preview":
+		oldContent, err := fileOp.GetContent(configPath)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := unifiedConfigDiff(string(oldContent), req.Content, configPath)
+		if err != nil {
+			return nil, err
+		}
+		res.Diff = diff
 	case "set":
 		file, err := fileOp.OpenFile(configPath)
 		if err != nil {
@@ -246,6 +739,9 @@ func (h *HostToolService) OperateToolConfig(req request.HostToolConfig) (*respon
 		if err != nil {
 			return nil, err
 		}
+		if diff, diffErr := unifiedConfigDiff(string(oldContent), req.Content, configPath); diffErr == nil && diff != "" {
+			global.LOG.Infof("[host tool] %s config changed:\n%s", req.Type, diff)
+		}
 		if err = fileOp.WriteFile(configPath, strings.NewReader(req.Content), fileInfo.Mode()); err != nil {
 			return nil, err
 		}
@@ -253,42 +749,378 @@ func (h *HostToolService) OperateToolConfig(req request.HostToolConfig) (*respon
 			_ = fileOp.WriteFile(configPath, bytes.NewReader(oldContent), fileInfo.Mode())
 			return nil, err
 		}
+	case "get-settings":
+		cfg, err := ini.Load(configPath)
+		if err != nil {
+			return nil, buserr.New("ErrConfigNotFound")
+		}
+		res.Settings = loadSupervisordSettings(cfg)
+	case "set-settings":
+		if err := validateSupervisordSettings(req.Settings); err != nil {
+			return nil, err
+		}
+		file, err := fileOp.OpenFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		oldContent, err := fileOp.GetContent(configPath)
+		if err != nil {
+			return nil, err
+		}
+		fileInfo, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := ini.Load(configPath)
+		if err != nil {
+			return nil, buserr.New("ErrConfigNotFound")
+		}
+		applySupervisordSettings(cfg, req.Settings)
+		var buf bytes.Buffer
+		if _, err = cfg.WriteTo(&buf); err != nil {
+			return nil, err
+		}
+		if err = fileOp.WriteFile(configPath, bytes.NewReader(buf.Bytes()), fileInfo.Mode()); err != nil {
+			return nil, err
+		}
+		if err = systemctl.Restart(serviceName); err != nil {
+			_ = fileOp.WriteFile(configPath, bytes.NewReader(oldContent), fileInfo.Mode())
+			return nil, err
+		}
+		res.Settings = req.Settings
+	case "repair":
+		file, err := fileOp.OpenFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		oldContent, err := fileOp.GetContent(configPath)
+		if err != nil {
+			return nil, err
+		}
+		fileInfo, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := ini.Load(configPath)
+		if err != nil {
+			return nil, buserr.New("ErrConfigNotFound")
+		}
+		if err = repairSupervisorInclude(cfg); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if _, err = cfg.WriteTo(&buf); err != nil {
+			return nil, err
+		}
+		if err = fileOp.WriteFile(configPath, bytes.NewReader(buf.Bytes()), fileInfo.Mode()); err != nil {
+			return nil, err
+		}
+		if err = systemctl.Restart(serviceName); err != nil {
+			_ = fileOp.WriteFile(configPath, bytes.NewReader(oldContent), fileInfo.Mode())
+			return nil, err
+		}
+		res.Content = buf.String()
 	}
 
 	return res, nil
 }
 
-func (h *HostToolService) GetToolLog(req request.HostToolLogReq) (string, error) {
-	fileOp := files.NewFileOp()
-	logfilePath := ""
-	switch req.Type {
-	case constant.Supervisord:
-		configPath := "/etc/supervisord.conf"
-		pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
-		if pathSet.ID != 0 || pathSet.Value != "" {
-			configPath = pathSet.Value
+// supervisordLogLevels are the loglevel values supervisord itself accepts;
+// anything else is silently ignored by supervisord at startup, which is
+// worse than rejecting it up front.
+var supervisordLogLevels = map[string]bool{
+	"critical": true,
+	"error":    true,
+	"warn":     true,
+	"info":     true,
+	"debug":    true,
+	"trace":    true,
+	"blather":  true,
+}
+
+// validateSupervisordSettings rejects values that would make supervisord
+// fail to start, so a bad edit can't make it past the request and break the
+// restart that follows.
+func validateSupervisordSettings(settings request.SupervisordSettings) error {
+	if settings.Loglevel != "" && !supervisordLogLevels[settings.Loglevel] {
+		return buserr.New("ErrInvalidLoglevel")
+	}
+	if settings.Minfds != "" {
+		if num, err := strconv.Atoi(settings.Minfds); err != nil || num < 1 {
+			return buserr.New("ErrInvalidSupervisordSetting")
 		}
-		logfilePath, _ = ini_conf.GetIniValue(configPath, "supervisord", "logfile")
 	}
-	oldContent, err := fileOp.GetContent(logfilePath)
-	if err != nil {
-		return "", err
+	if settings.Minprocs != "" {
+		if num, err := strconv.Atoi(settings.Minprocs); err != nil || num < 1 {
+			return buserr.New("ErrInvalidSupervisordSetting")
+		}
 	}
-	return string(oldContent), nil
+	return nil
+}
+
+// loadSupervisordSettings reads the subset of [supervisord] keys exposed for
+// structured editing, leaving a field empty when the key isn't set.
+func loadSupervisordSettings(cfg *ini.File) request.SupervisordSettings {
+	var settings request.SupervisordSettings
+	section, err := cfg.GetSection("supervisord")
+	if err != nil {
+		return settings
+	}
+	settings.Logfile = section.Key("logfile").Value()
+	settings.Loglevel = section.Key("loglevel").Value()
+	settings.Pidfile = section.Key("pidfile").Value()
+	settings.Minfds = section.Key("minfds").Value()
+	settings.Minprocs = section.Key("minprocs").Value()
+	return settings
+}
+
+// applySupervisordSettings writes only the non-empty fields of settings into
+// the [supervisord] section, leaving every other key (and every other
+// section) in cfg untouched.
+func applySupervisordSettings(cfg *ini.File, settings request.SupervisordSettings) {
+	section := cfg.Section("supervisord")
+	if settings.Logfile != "" {
+		section.Key("logfile").SetValue(settings.Logfile)
+	}
+	if settings.Loglevel != "" {
+		section.Key("loglevel").SetValue(settings.Loglevel)
+	}
+	if settings.Pidfile != "" {
+		section.Key("pidfile").SetValue(settings.Pidfile)
+	}
+	if settings.Minfds != "" {
+		section.Key("minfds").SetValue(settings.Minfds)
+	}
+	if settings.Minprocs != "" {
+		section.Key("minprocs").SetValue(settings.Minprocs)
+	}
+}
+
+// SetSupervisorInetConfig enables (or reconfigures) supervisord's XML-RPC
+// control interface by writing req's bind address and credentials into the
+// [inet_http_server] and [supervisorctl] sections of supervisord.conf, then
+// restarting supervisord to pick up the change. The config file and the
+// running service are both rolled back to their previous state if the
+// restart fails, so a bad address or a typo can't leave supervisord down.
+func (h *HostToolService) SetSupervisorInetConfig(req request.SupervisorInetConfigReq) error {
+	if err := validateSupervisorInetConfig(req); err != nil {
+		return err
+	}
+	configPath := "/etc/supervisord.conf"
+	pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
+	if pathSet.ID != 0 || pathSet.Value != "" {
+		configPath = pathSet.Value
+	}
+	serviceName := "supervisord"
+	serviceNameSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorServiceName))
+	if serviceNameSet.ID != 0 || serviceNameSet.Value != "" {
+		serviceName = serviceNameSet.Value
+	}
+
+	fileOp := files.NewFileOp()
+	file, err := fileOp.OpenFile(configPath)
+	if err != nil {
+		return err
+	}
+	oldContent, err := fileOp.GetContent(configPath)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return buserr.New("ErrConfigNotFound")
+	}
+	serverURL := applySupervisorInetConfig(cfg, req)
+	var buf bytes.Buffer
+	if _, err = cfg.WriteTo(&buf); err != nil {
+		return err
+	}
+	if err = fileOp.WriteFile(configPath, bytes.NewReader(buf.Bytes()), fileInfo.Mode()); err != nil {
+		return err
+	}
+	if err = systemctl.Restart(serviceName); err != nil {
+		_ = fileOp.WriteFile(configPath, bytes.NewReader(oldContent), fileInfo.Mode())
+		return err
+	}
+
+	serverURLSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorInetServerURL))
+	if serverURLSet.ID != 0 {
+		return settingRepo.Update(constant.SupervisorInetServerURL, serverURL)
+	}
+	return settingRepo.Create(constant.SupervisorInetServerURL, serverURL)
+}
+
+// SetSupervisorBinaries points supervisord/supervisorctl at an install
+// outside PATH (e.g. a Python venv or /opt). Either field left empty clears
+// the override, falling back to a plain PATH lookup of the bare name.
+func (h *HostToolService) SetSupervisorBinaries(req request.SupervisorBinariesReq) error {
+	if err := validateSupervisorBinaryPath(req.SupervisordBinary); err != nil {
+		return err
+	}
+	if err := validateSupervisorBinaryPath(req.SupervisorctlBinary); err != nil {
+		return err
+	}
+	if err := upsertSetting(constant.SupervisordBinary, req.SupervisordBinary); err != nil {
+		return err
+	}
+	return upsertSetting(constant.SupervisorctlBinary, req.SupervisorctlBinary)
+}
+
+// validateSupervisorBinaryPath accepts an empty path (falls back to PATH
+// lookup of the bare name) and otherwise requires it resolve to an
+// executable file, the same check exec.Command itself would fail on later
+// but surfaced immediately instead of on the next supervisord/supervisorctl
+// invocation.
+func validateSupervisorBinaryPath(binaryPath string) error {
+	if binaryPath == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(binaryPath); err != nil {
+		return buserr.WithMap("ErrSupervisorBinaryNotExecutable", map[string]interface{}{"path": binaryPath}, err)
+	}
+	return nil
+}
+
+// validateSupervisorInetConfig rejects a bind address supervisord can't
+// listen on, and a half-set credential pair supervisord would otherwise
+// silently treat as "no authentication" for.
+func validateSupervisorInetConfig(req request.SupervisorInetConfigReq) error {
+	if _, _, err := net.SplitHostPort(req.BindAddress); err != nil {
+		return buserr.New("ErrInvalidInetBindAddress")
+	}
+	if (req.Username == "") != (req.Password == "") {
+		return buserr.New("ErrInetCredentialsIncomplete")
+	}
+	return nil
+}
+
+// applySupervisorInetConfig writes req into cfg's [inet_http_server] and
+// [supervisorctl] sections (creating either if it doesn't already exist)
+// and returns the resulting serverurl. The stored [inet_http_server]
+// password is SHA1-hashed with supervisord's "{SHA}" prefix, the form it
+// expects so the plaintext password is never held on disk in that section;
+// [supervisorctl] still gets the plaintext password, since that's what
+// supervisorctl itself sends when authenticating against the server.
+func applySupervisorInetConfig(cfg *ini.File, req request.SupervisorInetConfigReq) string {
+	serverURL := fmt.Sprintf("http://%s", req.BindAddress)
+
+	inetSection := cfg.Section("inet_http_server")
+	inetSection.Key("port").SetValue(req.BindAddress)
+
+	ctlSection := cfg.Section("supervisorctl")
+	ctlSection.Key("serverurl").SetValue(serverURL)
+
+	if req.Username != "" {
+		inetSection.Key("username").SetValue(req.Username)
+		inetSection.Key("password").SetValue(hashSupervisorPassword(req.Password))
+		ctlSection.Key("username").SetValue(req.Username)
+		ctlSection.Key("password").SetValue(req.Password)
+	} else {
+		inetSection.DeleteKey("username")
+		inetSection.DeleteKey("password")
+		ctlSection.DeleteKey("username")
+		ctlSection.DeleteKey("password")
+	}
+	return serverURL
+}
+
+// hashSupervisorPassword returns password in supervisord's "{SHA}<hex>"
+// stored-password format (see supervisord's inet_http_server docs), so the
+// plaintext password isn't persisted in supervisord.conf.
+func hashSupervisorPassword(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return fmt.Sprintf("{SHA}%s", hex.EncodeToString(sum[:]))
+}
+
+func (h *HostToolService) GetToolLog(req request.HostToolLogReq) (response.ToolLogInfo, error) {
+	var info response.ToolLogInfo
+	switch req.Type {
+	case constant.Supervisord:
+		configPath := "/etc/supervisord.conf"
+		pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
+		if pathSet.ID != 0 || pathSet.Value != "" {
+			configPath = pathSet.Value
+		}
+		cfg, err := ini.Load(configPath)
+		if err != nil {
+			return info, buserr.New("ErrConfigNotFound")
+		}
+		logPath, source, err := resolveSupervisordLogPath(cfg)
+		if err != nil {
+			return info, err
+		}
+		info.LogPath = logPath
+		info.Source = source
+		if section, err := cfg.GetSection("supervisord"); err == nil {
+			if maxbytes, _ := section.GetKey("logfile_maxbytes"); maxbytes != nil && maxbytes.Value() != "" {
+				info.MaxBytes = parseSupervisorByteSize(maxbytes.Value())
+			}
+			if backups, _ := section.GetKey("logfile_backups"); backups != nil && backups.Value() != "" {
+				if parsed, err := strconv.Atoi(backups.Value()); err == nil {
+					info.Backups = parsed
+				}
+			}
+		}
+		if source == "file" {
+			content, err := files.NewFileOp().GetContent(logPath)
+			if err != nil {
+				return info, err
+			}
+			info.Content = string(content)
+		}
+	}
+	return info, nil
+}
+
+// defaultSupervisordLogPath is where supervisord writes its own log when
+// logfile is left at its "AUTO" default.
+const defaultSupervisordLogPath = "/var/log/supervisor/supervisord.log"
+
+// resolveSupervisordLogPath interprets the [supervisord] section's logfile
+// key, handling the special AUTO/syslog values supervisord accepts instead
+// of treating them as literal file paths. It returns the resolved path (or
+// a description of where to look when it isn't a plain file) and a source
+// tag of "file" or "syslog" so callers know whether the path can actually
+// be read off disk.
+func resolveSupervisordLogPath(cfg *ini.File) (path string, source string, err error) {
+	section, sectionErr := cfg.GetSection("supervisord")
+	if sectionErr != nil {
+		return defaultSupervisordLogPath, "file", nil
+	}
+	logfileKey, keyErr := section.GetKey("logfile")
+	if keyErr != nil || logfileKey.Value() == "" {
+		return defaultSupervisordLogPath, "file", nil
+	}
+	switch strings.ToUpper(logfileKey.Value()) {
+	case "AUTO":
+		return defaultSupervisordLogPath, "file", nil
+	case "SYSLOG":
+		if managerName, available := systemctl.ActiveManager(); available && managerName == "systemd" {
+			return "journalctl -u supervisord", "syslog", nil
+		}
+		return "/var/log/syslog", "syslog", nil
+	}
+	return logfileKey.Value(), "file", nil
 }
 
 func (h *HostToolService) OperateSupervisorProcess(req request.SupervisorProcessConfig) error {
 	var (
-		supervisordDir = path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord")
-		logDir         = path.Join(supervisordDir, "log")
-		includeDir     = path.Join(supervisordDir, "supervisor.d")
-		outLog         = path.Join(logDir, fmt.Sprintf("%s.out.log", req.Name))
+		logDir     = resolveSupervisorLogDir()
+		includeDir = resolveManagedSupervisorIncludeDir()
+		outLog     = path.Join(logDir, fmt.Sprintf("%s.out.log", req.Name))
 		errLog         = path.Join(logDir, fmt.Sprintf("%s.err.log", req.Name))
 		iniPath        = path.Join(includeDir, fmt.Sprintf("%s.ini", req.Name))
 		fileOp         = files.NewFileOp()
 	)
 	if req.Operate == "update" || req.Operate == "create" {
-		if !fileOp.Stat(req.Dir) {
+		if strings.TrimSpace(req.Command) == "" {
+			return buserr.New("ErrConfigCommandRequired")
+		}
+		if req.Dir != "" && !fileOp.Stat(req.Dir) {
 			return buserr.New("ErrConfigDirNotFound")
 		}
 		_, err := user.Lookup(req.User)
@@ -298,17 +1130,42 @@ func (h *HostToolService) OperateSupervisorProcess(req request.SupervisorProcess
 	}
 
 	switch req.Operate {
+	case "import":
+		name, cfg, section, err := parseImportedSupervisorConfig(req.ImportContent)
+		if err != nil {
+			return err
+		}
+		importedIniPath := path.Join(includeDir, fmt.Sprintf("%s.ini", name))
+		if fileOp.Stat(importedIniPath) {
+			return buserr.New("ErrConfigAlreadyExist")
+		}
+		if !req.PreserveLogPaths {
+			normalizeImportedLogPaths(section, logDir, name)
+		}
+		if err = cfg.SaveTo(importedIniPath); err != nil {
+			return err
+		}
+		if err := operateSupervisorCtl("reread", "", ""); err != nil {
+			return err
+		}
+		return operateSupervisorCtl("update", "", "")
 	case "create":
 		if fileOp.Stat(iniPath) {
 			return buserr.New("ErrConfigAlreadyExist")
 		}
+		numprocs, err := validateNumprocs(req.Numprocs)
+		if err != nil {
+			return err
+		}
 		configFile := ini.Empty()
 		section, err := configFile.NewSection(fmt.Sprintf("program:%s", req.Name))
 		if err != nil {
 			return err
 		}
 		_, _ = section.NewKey("command", req.Command)
-		_, _ = section.NewKey("directory", req.Dir)
+		if req.Dir != "" {
+			_, _ = section.NewKey("directory", req.Dir)
+		}
 		_, _ = section.NewKey("autorestart", "true")
 		_, _ = section.NewKey("startsecs", "3")
 		_, _ = section.NewKey("stdout_logfile", outLog)
@@ -316,18 +1173,27 @@ func (h *HostToolService) OperateSupervisorProcess(req request.SupervisorProcess
 		_, _ = section.NewKey("stdout_logfile_maxbytes", "2MB")
 		_, _ = section.NewKey("stderr_logfile_maxbytes", "2MB")
 		_, _ = section.NewKey("user", req.User)
-		_, _ = section.NewKey("priority", "999")
-		_, _ = section.NewKey("numprocs", req.Numprocs)
-		_, _ = section.NewKey("process_name", "%(program_name)s_%(process_num)02d")
+		_, _ = section.NewKey("priority", strconv.Itoa(supervisorProcessPriority(req.Priority)))
+		_, _ = section.NewKey("numprocs", numprocs)
+		_, _ = section.NewKey("process_name", supervisorProcessName(numprocs))
 
 		if err = configFile.SaveTo(iniPath); err != nil {
 			return err
 		}
+		if req.Group != "" {
+			if err := addSupervisorProcessToGroup(includeDir, req.Group, req.Name); err != nil {
+				return err
+			}
+		}
 		if err := operateSupervisorCtl("reread", "", ""); err != nil {
 			return err
 		}
 		return operateSupervisorCtl("update", "", "")
 	case "update":
+		numprocs, err := validateNumprocs(req.Numprocs)
+		if err != nil {
+			return err
+		}
 		configFile, err := ini.Load(iniPath)
 		if err != nil {
 			return err
@@ -339,27 +1205,58 @@ func (h *HostToolService) OperateSupervisorProcess(req request.SupervisorProcess
 
 		commandKey := section.Key("command")
 		commandKey.SetValue(req.Command)
-		directoryKey := section.Key("directory")
-		directoryKey.SetValue(req.Dir)
+		if req.Dir != "" {
+			directoryKey := section.Key("directory")
+			directoryKey.SetValue(req.Dir)
+		} else {
+			section.DeleteKey("directory")
+		}
 		userKey := section.Key("user")
 		userKey.SetValue(req.User)
 		numprocsKey := section.Key("numprocs")
-		numprocsKey.SetValue(req.Numprocs)
+		numprocsKey.SetValue(numprocs)
+		processNameKey := section.Key("process_name")
+		processNameKey.SetValue(supervisorProcessName(numprocs))
+		priorityKey := section.Key("priority")
+		priorityKey.SetValue(strconv.Itoa(supervisorProcessPriority(req.Priority)))
 
 		if err = configFile.SaveTo(iniPath); err != nil {
 			return err
 		}
+		if req.Group != "" {
+			if err := addSupervisorProcessToGroup(includeDir, req.Group, req.Name); err != nil {
+				return err
+			}
+		}
 		if err := operateSupervisorCtl("reread", "", ""); err != nil {
 			return err
 		}
 		return operateSupervisorCtl("update", "", "")
 	case "restart":
+		if group := supervisorGroupForProcess(includeDir, req.Name); group != "" {
+			return operateSupervisorCtl("restart", "", fmt.Sprintf("%s:*", group))
+		}
 		return operateSupervisorCtl("restart", req.Name, "")
 	case "start":
-		return operateSupervisorCtl("start", req.Name, "")
+		if group := supervisorGroupForProcess(includeDir, req.Name); group != "" {
+			return operateSupervisorCtl("start", "", fmt.Sprintf("%s:*", group))
+		}
+		return operateSupervisorProcessAction(req.Name, true)
 	case "stop":
-		return operateSupervisorCtl("stop", req.Name, "")
+		if group := supervisorGroupForProcess(includeDir, req.Name); group != "" {
+			return operateSupervisorCtl("stop", "", fmt.Sprintf("%s:*", group))
+		}
+		if req.Force {
+			_, err := forceStopSupervisorProcess(req.Name)
+			return err
+		}
+		return operateSupervisorProcessAction(req.Name, false)
 	case "delete":
+		if req.Force {
+			if _, err := forceStopSupervisorProcess(req.Name); err != nil {
+				global.LOG.Warnf("force-stop before deleting supervisor process %q failed: %v", req.Name, err)
+			}
+		}
 		_ = operateSupervisorCtl("remove", "", req.Name)
 		_ = files.NewFileOp().DeleteFile(iniPath)
 		_ = files.NewFileOp().DeleteFile(outLog)
@@ -373,11 +1270,335 @@ func (h *HostToolService) OperateSupervisorProcess(req request.SupervisorProcess
 	return nil
 }
 
+// testSupervisorCommandTimeout bounds how long TestSupervisorProcessCommand
+// lets a candidate command run before it's killed and reported as timed
+// out -- long enough to catch an immediate crash, short enough that a
+// command which is actually a long-running daemon (the common mistake this
+// endpoint exists to catch) doesn't hang the request.
+const testSupervisorCommandTimeout = 5 * time.Second
+
+// TestSupervisorProcessCommand runs a candidate program command for a
+// bounded time as the target user, without writing an ini or touching
+// supervisord at all, so a bad command is caught immediately instead of
+// only after create/update and a trip to the program's FATAL-state log.
+func (h *HostToolService) TestSupervisorProcessCommand(req request.SupervisorProcessTestReq) (*response.SupervisorProcessTestResult, error) {
+	if strings.TrimSpace(req.Command) == "" {
+		return nil, buserr.New("ErrConfigCommandRequired")
+	}
+	if req.Dir != "" && !files.NewFileOp().Stat(req.Dir) {
+		return nil, buserr.New("ErrConfigDirNotFound")
+	}
+	runAsUser, err := user.Lookup(req.User)
+	if err != nil {
+		return nil, buserr.WithMap("ErrUserFindErr", map[string]interface{}{"name": req.User, "err": err.Error()}, err)
+	}
+	uid, err := strconv.ParseUint(runAsUser.Uid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := strconv.ParseUint(runAsUser.Gid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testSupervisorCommandTimeout)
+	defer cancel()
+
+	testCmd := exec.CommandContext(ctx, "sh", "-c", req.Command)
+	testCmd.Dir = req.Dir
+	testCmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}}
+	var stdout, stderr bytes.Buffer
+	testCmd.Stdout = &stdout
+	testCmd.Stderr = &stderr
+
+	runErr := testCmd.Run()
+	result := &response.SupervisorProcessTestResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		return result, nil
+	}
+	if testCmd.ProcessState != nil {
+		result.ExitCode = testCmd.ProcessState.ExitCode()
+	} else if runErr != nil {
+		result.ExitCode = -1
+	}
+	return result, nil
+}
+
+// OperateSupervisorReload drives the two daemon-wide operate types that
+// aren't scoped to a single process: "reread" previews what a reload would
+// change without applying it, and "reload" applies it (reread every config
+// and restart whatever changed) in one call instead of the
+// reread-then-update dance OperateSupervisorProcess does per process.
+func (h *HostToolService) OperateSupervisorReload(req request.SupervisorReloadReq) (*response.SupervisorReread, error) {
+	switch req.Operate {
+	case "reread":
+		output, err := supervisorCtlOutput("reread", "", "")
+		if err != nil {
+			return nil, err
+		}
+		added, changed, removed := parseSupervisorRereadOutput(output)
+		return &response.SupervisorReread{Added: added, Changed: changed, Removed: removed}, nil
+	case "reload":
+		if err := operateSupervisorCtl("reload", "", ""); err != nil {
+			return nil, err
+		}
+		return &response.SupervisorReread{}, nil
+	}
+	return nil, nil
+}
+
+// parseSupervisorRereadOutput parses `supervisorctl reread`'s output into
+// the added/changed/removed program names it reports, one per line as
+// "<name>: available" (added), "<name>: changed", or "<name>: disappeared"
+// (removed) -- the three outcomes supervisorctl's reread command prints.
+// Lines that don't match any of the three suffixes (e.g. a trailing blank
+// line) are ignored rather than erroring.
+func parseSupervisorRereadOutput(output string) (added, changed, removed []string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, status, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		switch strings.TrimSpace(status) {
+		case "available":
+			added = append(added, name)
+		case "changed":
+			changed = append(changed, name)
+		case "disappeared":
+			removed = append(removed, name)
+		}
+	}
+	return added, changed, removed
+}
+
+// parseImportedSupervisorConfig parses a hand-written supervisord program ini
+// and returns the program's name along with the parsed file and section, so
+// the caller can rewrite fields (like log paths) before saving it into our
+// managed supervisor.d directory. It's rejected unless the content parses as
+// valid ini and contains exactly one [program:<name>] section, since a
+// multi-program or group file can't be mapped onto a single imported process.
+func parseImportedSupervisorConfig(content string) (string, *ini.File, *ini.Section, error) {
+	cfg, err := ini.Load([]byte(content))
+	if err != nil {
+		return "", nil, nil, buserr.New("ErrImportConfigInvalid")
+	}
+	var programSections []*ini.Section
+	for _, section := range cfg.Sections() {
+		if strings.HasPrefix(section.Name(), "program:") {
+			programSections = append(programSections, section)
+		}
+	}
+	if len(programSections) != 1 {
+		return "", nil, nil, buserr.New("ErrImportConfigInvalid")
+	}
+	section := programSections[0]
+	name := strings.TrimPrefix(section.Name(), "program:")
+	if name == "" {
+		return "", nil, nil, buserr.New("ErrImportConfigInvalid")
+	}
+	return name, cfg, section, nil
+}
+
+// normalizeImportedLogPaths rewrites an imported program's stdout/stderr log
+// paths to live under our managed log directory, so an adopted config
+// doesn't keep writing logs to wherever the original hand-written setup put
+// them (which may not even be writable by the user 1Panel runs programs as).
+func normalizeImportedLogPaths(section *ini.Section, logDir, name string) {
+	if key, err := section.GetKey("stdout_logfile"); err == nil && key.Value() != "" {
+		key.SetValue(path.Join(logDir, fmt.Sprintf("%s.out.log", name)))
+	}
+	if key, err := section.GetKey("stderr_logfile"); err == nil && key.Value() != "" {
+		key.SetValue(path.Join(logDir, fmt.Sprintf("%s.err.log", name)))
+	}
+}
+
+// ExportSupervisorProcessConfigs bundles every managed program's .ini file
+// (and, if requested, its stdout/stderr logs) from supervisor.d into a
+// deterministic tar.gz, so operators have a single file to keep for disaster
+// recovery of their process definitions. The returned path is meant to be
+// streamed straight back to the caller, the same way
+// GetSupervisorProcessLogPath's result is.
+func (h *HostToolService) ExportSupervisorProcessConfigs(req request.SupervisorConfigsBackupReq) (string, error) {
+	includeDir := resolveSupervisorIncludeDir()
+	entries, err := os.ReadDir(includeDir)
+	if err != nil {
+		return "", err
+	}
+	fileOp := files.NewFileOp()
+	logDir := resolveSupervisorLogDir()
+	var srcFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ini") {
+			continue
+		}
+		srcFiles = append(srcFiles, path.Join(includeDir, entry.Name()))
+		if req.WithLogs {
+			name := strings.TrimSuffix(entry.Name(), ".ini")
+			for _, suffix := range []string{"out.log", "err.log"} {
+				logPath := path.Join(logDir, fmt.Sprintf("%s.%s", name, suffix))
+				if fileOp.Stat(logPath) {
+					srcFiles = append(srcFiles, logPath)
+				}
+			}
+		}
+	}
+	if len(srcFiles) == 0 {
+		return "", buserr.New("ErrNoSupervisorProcessToExport")
+	}
+	sort.Strings(srcFiles)
+
+	tempDir := path.Join(os.TempDir(), fmt.Sprintf("supervisor-export-%d", time.Now().UnixNano()))
+	const archiveName = "supervisor-configs.tar.gz"
+	if err := fileOp.Compress(srcFiles, tempDir, archiveName, files.TarGz); err != nil {
+		return "", err
+	}
+	return path.Join(tempDir, archiveName), nil
+}
+
+// ImportSupervisorProcessConfigs restores managed program configs previously
+// captured by ExportSupervisorProcessConfigs: each .ini in the tarball goes
+// through the same single-program-section validation as a plain import
+// before being copied into supervisor.d, in deterministic (sorted) order, so
+// one malformed or duplicate entry in the bundle can't corrupt configs that
+// were fine.
+func (h *HostToolService) ImportSupervisorProcessConfigs(req request.SupervisorConfigsRestoreReq) error {
+	includeDir := resolveSupervisorIncludeDir()
+	tempDir := path.Join(os.TempDir(), fmt.Sprintf("supervisor-import-%d", time.Now().UnixNano()))
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	if err := handleUnTar(req.TarPath, tempDir); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	fileOp := files.NewFileOp()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ini") {
+			continue
+		}
+		content, err := fileOp.GetContent(path.Join(tempDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		name, cfg, _, err := parseImportedSupervisorConfig(string(content))
+		if err != nil {
+			return err
+		}
+		importedIniPath := path.Join(includeDir, fmt.Sprintf("%s.ini", name))
+		if fileOp.Stat(importedIniPath) {
+			return buserr.New("ErrConfigAlreadyExist")
+		}
+		if err = cfg.SaveTo(importedIniPath); err != nil {
+			return err
+		}
+	}
+	if err := operateSupervisorCtl("reread", "", ""); err != nil {
+		return err
+	}
+	return operateSupervisorCtl("update", "", "")
+}
+
+// supervisorIncludeDirFromConfig reads the [include] files glob out of an
+// already-loaded supervisord.conf and returns the directory it points at,
+// so a pre-existing supervisor install (e.g. /etc/supervisor/conf.d) is
+// picked up instead of always assuming our own managed layout. Falls back
+// to managedDir when the conf has no usable include directive.
+func supervisorIncludeDirFromConfig(cfg *ini.File, managedDir string) string {
+	section, err := cfg.GetSection("include")
+	if err != nil {
+		return managedDir
+	}
+	filesKey, err := section.GetKey("files")
+	if err != nil || filesKey.Value() == "" {
+		return managedDir
+	}
+	dir := path.Dir(filesKey.Value())
+	if dir == "" || dir == "." {
+		return managedDir
+	}
+	return dir
+}
+
+// resolveSupervisorBaseDir returns the directory 1Panel manages supervisord's
+// own files under when the operator hasn't redirected anything elsewhere.
+func resolveSupervisorBaseDir() string {
+	return path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord")
+}
+
+// resolveManagedSupervisorIncludeDir returns the configured
+// SupervisorIncludeDir setting, falling back to
+// BaseDir/1panel/tools/supervisord/supervisor.d when unset.
+func resolveManagedSupervisorIncludeDir() string {
+	dirSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorIncludeDir))
+	if dirSet.Value != "" {
+		return dirSet.Value
+	}
+	return path.Join(resolveSupervisorBaseDir(), "supervisor.d")
+}
+
+// resolveSupervisorLogDir returns the configured SupervisorLogDir setting,
+// falling back to BaseDir/1panel/tools/supervisord/log when unset.
+func resolveSupervisorLogDir() string {
+	dirSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorLogDir))
+	if dirSet.Value != "" {
+		return dirSet.Value
+	}
+	return path.Join(resolveSupervisorBaseDir(), "log")
+}
+
+// resolveSupervisordBinary returns the configured SupervisordBinary setting
+// (an absolute path, for an install outside PATH), falling back to the bare
+// "supervisord" name so cmd.Which/exec.LookPath resolve it off PATH as before.
+func resolveSupervisordBinary() string {
+	binSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisordBinary))
+	if binSet.Value != "" {
+		return binSet.Value
+	}
+	return constant.Supervisord
+}
+
+// resolveSupervisorctlBinary is resolveSupervisordBinary's counterpart for
+// SupervisorctlBinary, falling back to the bare "supervisorctl" name.
+func resolveSupervisorctlBinary() string {
+	binSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorctlBinary))
+	if binSet.Value != "" {
+		return binSet.Value
+	}
+	return "supervisorctl"
+}
+
+// resolveSupervisorIncludeDir loads the configured supervisord.conf and
+// returns its actual include directory, falling back to the directory we
+// manage ourselves when the conf can't be read or parsed.
+func resolveSupervisorIncludeDir() string {
+	managedDir := resolveManagedSupervisorIncludeDir()
+	configPath := "/etc/supervisord.conf"
+	pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
+	if pathSet.ID != 0 || pathSet.Value != "" {
+		configPath = pathSet.Value
+	}
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return managedDir
+	}
+	return supervisorIncludeDirFromConfig(cfg, managedDir)
+}
+
 func (h *HostToolService) GetSupervisorProcessConfig() ([]response.SupervisorProcessConfig, error) {
 	var (
 		result []response.SupervisorProcessConfig
 	)
-	configDir := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d")
+	configDir := resolveSupervisorIncludeDir()
 	fileList, _ := NewIFileService().GetFileList(request.FileOption{FileOption: files.FileOption{Path: configDir, Expand: true, Page: 1, PageSize: 100}})
 	if len(fileList.Items) == 0 {
 		return result, nil
@@ -394,7 +1615,8 @@ func (h *HostToolService) GetSupervisorProcessConfig() ([]response.SupervisorPro
 			config.Name = name
 			section, err := f.GetSection(fmt.Sprintf("program:%s", name))
 			if err != nil {
-				global.LOG.Errorf("get %s file section err %s", configFile.Name, err.Error())
+				// Not a program entry (e.g. an eventlistener section),
+				// which is surfaced separately by GetSupervisorEventListeners.
 				continue
 			}
 			if command, _ := section.GetKey("command"); command != nil {
@@ -409,32 +1631,138 @@ func (h *HostToolService) GetSupervisorProcessConfig() ([]response.SupervisorPro
 			if numprocs, _ := section.GetKey("numprocs"); numprocs != nil {
 				config.Numprocs = numprocs.Value()
 			}
+			config.Priority = supervisorProcessPriority(nil)
+			if priority, _ := section.GetKey("priority"); priority != nil && priority.Value() != "" {
+				if parsed, err := strconv.Atoi(priority.Value()); err == nil {
+					config.Priority = parsed
+				}
+			}
+			config.Group = supervisorGroupForProcess(configDir, name)
 			_ = getProcessStatus(&config)
+			config.LogFiles = getProcessLogStatus(section)
 			result = append(result, config)
 		}
 	}
 	return result, nil
 }
 
-func (h *HostToolService) OperateSupervisorProcessFile(req request.SupervisorProcessFileReq) (string, error) {
-	var (
-		fileOp     = files.NewFileOp()
-		group      = fmt.Sprintf("program:%s", req.Name)
-		configPath = path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d", fmt.Sprintf("%s.ini", req.Name))
-	)
-	switch req.File {
-	case "err.log":
-		logPath, err := ini_conf.GetIniValue(configPath, group, "stderr_logfile")
-		if err != nil {
-			return "", err
-		}
-		switch req.Operate {
+// GetSupervisorEventListeners enumerates `[eventlistener:<name>]` sections
+// out of supervisor.d the same way GetSupervisorProcessConfig enumerates
+// `[program:<name>]` sections, since the two share a directory and an ini
+// file can only belong to one kind of section.
+func (h *HostToolService) GetSupervisorEventListeners() ([]response.SupervisorEventListenerConfig, error) {
+	var result []response.SupervisorEventListenerConfig
+	configDir := resolveSupervisorIncludeDir()
+	fileList, _ := NewIFileService().GetFileList(request.FileOption{FileOption: files.FileOption{Path: configDir, Expand: true, Page: 1, PageSize: 100}})
+	if len(fileList.Items) == 0 {
+		return result, nil
+	}
+	for _, configFile := range fileList.Items {
+		if !strings.HasSuffix(configFile.Name, ".ini") {
+			continue
+		}
+		f, err := ini.Load(configFile.Path)
+		if err != nil {
+			global.LOG.Errorf("get %s file err %s", configFile.Name, err.Error())
+			continue
+		}
+		name := strings.TrimSuffix(configFile.Name, ".ini")
+		section, err := f.GetSection(fmt.Sprintf("eventlistener:%s", name))
+		if err != nil {
+			continue
+		}
+		config := response.SupervisorEventListenerConfig{Name: name}
+		if command, _ := section.GetKey("command"); command != nil {
+			config.Command = command.Value()
+		}
+		if events, _ := section.GetKey("events"); events != nil {
+			config.Events = events.Value()
+		}
+		if bufferSize, _ := section.GetKey("buffer_size"); bufferSize != nil {
+			config.BufferSize = bufferSize.Value()
+		}
+		result = append(result, config)
+	}
+	return result, nil
+}
+
+// OperateSupervisorEventListener creates or deletes a supervisord
+// eventlistener, in its own `<name>.ini` file under supervisor.d, the same
+// layout a program uses. Update isn't supported: listeners are small and
+// re-created cheaply, unlike a program config with a log history worth
+// preserving.
+func (h *HostToolService) OperateSupervisorEventListener(req request.SupervisorEventListenerConfig) error {
+	var (
+		includeDir = resolveManagedSupervisorIncludeDir()
+		iniPath    = path.Join(includeDir, fmt.Sprintf("%s.ini", req.Name))
+		fileOp     = files.NewFileOp()
+	)
+	switch req.Operate {
+	case "create":
+		if fileOp.Stat(iniPath) {
+			return buserr.New("ErrConfigAlreadyExist")
+		}
+		bufferSize, err := validateEventListenerBufferSize(req.BufferSize)
+		if err != nil {
+			return err
+		}
+		configFile := ini.Empty()
+		section, err := configFile.NewSection(fmt.Sprintf("eventlistener:%s", req.Name))
+		if err != nil {
+			return err
+		}
+		_, _ = section.NewKey("command", req.Command)
+		_, _ = section.NewKey("events", req.Events)
+		if bufferSize != "" {
+			_, _ = section.NewKey("buffer_size", bufferSize)
+		}
+		if err = configFile.SaveTo(iniPath); err != nil {
+			return err
+		}
+		if err := operateSupervisorCtl("reread", "", ""); err != nil {
+			return err
+		}
+		return operateSupervisorCtl("update", "", "")
+	case "delete":
+		_ = operateSupervisorCtl("remove", "", req.Name)
+		_ = fileOp.DeleteFile(iniPath)
+		if err := operateSupervisorCtl("reread", "", ""); err != nil {
+			return err
+		}
+		return operateSupervisorCtl("update", "", "")
+	}
+	return nil
+}
+
+// validateEventListenerBufferSize rejects a non-empty BufferSize that isn't
+// a positive integer up front, rather than writing a broken ini that only
+// fails later, at reread time, with ErrConfigParse.
+func validateEventListenerBufferSize(bufferSize string) (string, error) {
+	if bufferSize == "" {
+		return "", nil
+	}
+	num, err := strconv.Atoi(bufferSize)
+	if err != nil || num < 1 {
+		return "", buserr.New("ErrInvalidBufferSize")
+	}
+	return bufferSize, nil
+}
+
+func (h *HostToolService) OperateSupervisorProcessFile(req request.SupervisorProcessFileReq) (string, error) {
+	var (
+		fileOp     = files.NewFileOp()
+		group      = fmt.Sprintf("program:%s", req.Name)
+		configPath = path.Join(resolveManagedSupervisorIncludeDir(), fmt.Sprintf("%s.ini", req.Name))
+	)
+	switch req.File {
+	case "err.log":
+		logPath, err := ini_conf.GetIniValue(configPath, group, "stderr_logfile")
+		if err != nil {
+			return "", err
+		}
+		switch req.Operate {
 		case "get":
-			content, err := fileOp.GetContent(logPath)
-			if err != nil {
-				return "", err
-			}
-			return string(content), nil
+			return ViewConfig(logPath, req.ConfigOption)
 		case "clear":
 			if err = fileOp.WriteFile(logPath, strings.NewReader(""), 0755); err != nil {
 				return "", err
@@ -448,11 +1776,7 @@ func (h *HostToolService) OperateSupervisorProcessFile(req request.SupervisorPro
 		}
 		switch req.Operate {
 		case "get":
-			content, err := fileOp.GetContent(logPath)
-			if err != nil {
-				return "", err
-			}
-			return string(content), nil
+			return ViewConfig(logPath, req.ConfigOption)
 		case "clear":
 			if err = fileOp.WriteFile(logPath, strings.NewReader(""), 0755); err != nil {
 				return "", err
@@ -462,11 +1786,7 @@ func (h *HostToolService) OperateSupervisorProcessFile(req request.SupervisorPro
 	case "config":
 		switch req.Operate {
 		case "get":
-			content, err := fileOp.GetContent(configPath)
-			if err != nil {
-				return "", err
-			}
-			return string(content), nil
+			return ViewConfig(configPath, req.ConfigOption)
 		case "update":
 			if req.Content == "" {
 				return "", buserr.New("ErrConfigIsNull")
@@ -481,32 +1801,408 @@ func (h *HostToolService) OperateSupervisorProcessFile(req request.SupervisorPro
 	return "", nil
 }
 
-func operateSupervisorCtl(operate, name, group string) error {
-	processNames := []string{operate}
-	if name != "" {
-		includeDir := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d")
-		f, err := ini.Load(path.Join(includeDir, fmt.Sprintf("%s.ini", name)))
-		if err != nil {
+// ValidateSupervisorProcessConfig parses req.Content as ini and checks it
+// against what supervisord requires of a program section -- a `command` key
+// set, and the section name matching req.Name -- without writing anything,
+// so a mistake surfaces before OperateSupervisorProcessFile's "update"
+// operation ever touches the file on disk.
+func (h *HostToolService) ValidateSupervisorProcessConfig(req request.SupervisorProcessFileReq) (response.SupervisorConfigValidation, error) {
+	return validateSupervisorProcessConfig(req.Content, req.Name)
+}
+
+func validateSupervisorProcessConfig(content, name string) (response.SupervisorConfigValidation, error) {
+	cfg, err := ini.Load([]byte(content))
+	if err != nil {
+		return response.SupervisorConfigValidation{}, buserr.New("ErrImportConfigInvalid")
+	}
+
+	result := response.SupervisorConfigValidation{Valid: true}
+	wantSection := fmt.Sprintf("program:%s", name)
+	section, sectionErr := cfg.GetSection(wantSection)
+	if sectionErr != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, response.SupervisorConfigValidationItem{
+			Section: wantSection,
+			Line:    findSectionLine(content, wantSection),
+			Message: fmt.Sprintf("missing a [%s] section matching the process name", wantSection),
+		})
+		for _, s := range cfg.Sections() {
+			if strings.HasPrefix(s.Name(), "program:") {
+				section = s
+				wantSection = s.Name()
+				break
+			}
+		}
+	}
+	if section != nil {
+		if key, err := section.GetKey("command"); err != nil || key.Value() == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, response.SupervisorConfigValidationItem{
+				Section: wantSection,
+				Line:    findSectionLine(content, wantSection),
+				Message: "command is required",
+			})
+		}
+	}
+	return result, nil
+}
+
+// findSectionLine returns the 1-based line number of section's "[name]"
+// header in content, or 0 if it isn't present, so a validation error can
+// point a user at the right spot instead of just naming the section.
+func findSectionLine(content, section string) int {
+	header := fmt.Sprintf("[%s]", section)
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == header {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ViewConfig reads path and returns it per option: the whole file by
+// default, or the first HeadLines lines, or the last TailLines lines
+// (HeadLines wins if both are set), optionally filtered further to lines
+// matching the Grep regular expression. Filtering runs against content
+// already read into memory with Go's regexp package rather than shelling
+// out to head/tail/grep, so there's no argument-escaping concern for a
+// user-supplied pattern.
+func ViewConfig(path string, option request.ConfigOption) (string, error) {
+	content, err := files.NewFileOp().GetContent(path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(content), "\n")
+	switch {
+	case option.HeadLines > 0 && option.HeadLines < len(lines):
+		lines = lines[:option.HeadLines]
+	case option.TailLines > 0 && option.TailLines < len(lines):
+		lines = lines[len(lines)-option.TailLines:]
+	}
+	if option.Grep == "" {
+		return strings.Join(lines, "\n"), nil
+	}
+	re, err := regexp.Compile(option.Grep)
+	if err != nil {
+		return "", buserr.WithMap("ErrConfigParse", map[string]interface{}{}, err)
+	}
+	matched := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if re.MatchString(line) {
+			matched = append(matched, line)
+		}
+	}
+	return strings.Join(matched, "\n"), nil
+}
+
+// StreamSupervisorProcessLog copies src to dst, gzip-compressing on the fly
+// when gzipOut is set. It streams in fixed-size chunks via io.Copy rather
+// than reading the whole log into memory first, so a large log file doesn't
+// blow up process memory on download.
+func StreamSupervisorProcessLog(dst io.Writer, src io.Reader, gzipOut bool) error {
+	if !gzipOut {
+		_, err := io.Copy(dst, src)
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// GetSupervisorProcessLogPath resolves the stdout/stderr log file path
+// configured for a supervisor process, for the download endpoint to stream
+// directly from disk.
+func (h *HostToolService) GetSupervisorProcessLogPath(req request.SupervisorProcessFileReq) (string, error) {
+	configPath := path.Join(resolveManagedSupervisorIncludeDir(), fmt.Sprintf("%s.ini", req.Name))
+	group := fmt.Sprintf("program:%s", req.Name)
+	switch req.File {
+	case "err.log":
+		return ini_conf.GetIniValue(configPath, group, "stderr_logfile")
+	case "out.log":
+		return ini_conf.GetIniValue(configPath, group, "stdout_logfile")
+	default:
+		return "", buserr.New("ErrConfigParse")
+	}
+}
+
+// maxSupervisorLogFollowers caps concurrent "tail -f"-style websocket
+// followers, so a flood of open browser tabs watching process logs can't
+// exhaust the router with open file handles and goroutines.
+const maxSupervisorLogFollowers = 20
+
+var supervisorLogFollowers atomic.Int32
+
+// FollowSupervisorProcessLog streams newly appended lines of a supervisor
+// process's err.log/out.log to wsConn, like `tail -f`. It reopens the log
+// file when it's rotated (truncated or replaced) and returns as soon as the
+// client disconnects or asks to close.
+func (h *HostToolService) FollowSupervisorProcessLog(wsConn *websocket.Conn, req request.SupervisorProcessFileReq) error {
+	if supervisorLogFollowers.Add(1) > maxSupervisorLogFollowers {
+		supervisorLogFollowers.Add(-1)
+		return buserr.New("ErrTooManyLogFollowers")
+	}
+	defer supervisorLogFollowers.Add(-1)
+	defer wsConn.Close()
+
+	logPath, err := h.GetSupervisorProcessLogPath(req)
+	if err != nil {
+		return err
+	}
+
+	closeCh := make(chan struct{})
+	go func() {
+		for {
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				close(closeCh)
+				return
+			}
+		}
+	}()
+
+	return followLogFile(logPath, closeCh, func(data []byte) error {
+		return wsConn.WriteMessage(websocket.TextMessage, data)
+	})
+}
+
+// followLogFile tails path like `tail -f`: it polls for new content and
+// transparently reopens the file when it's been rotated, covering both
+// copytruncate (size drops below the last read offset) and rename-then-
+// recreate (the path now resolves to a different underlying file). It
+// returns nil when closeCh is closed, or the error onData returns.
+func followLogFile(path string, closeCh <-chan struct{}, onData func([]byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	buffer := make([]byte, 32*1024)
+
+	for {
+		select {
+		case <-closeCh:
+			return nil
+		case <-ticker.C:
+			if currentInfo, statErr := os.Stat(path); statErr == nil {
+				if !os.SameFile(info, currentInfo) || currentInfo.Size() < offset {
+					if reopened, openErr := os.Open(path); openErr == nil {
+						_ = file.Close()
+						file = reopened
+						info = currentInfo
+						offset = 0
+						if _, err := file.Seek(offset, io.SeekStart); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			for {
+				n, readErr := file.Read(buffer)
+				if n > 0 {
+					offset += int64(n)
+					if err := onData(buffer[:n]); err != nil {
+						return err
+					}
+				}
+				if readErr != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// supervisorCtlLookup resolves the supervisorctl binary; overridden in tests
+// so missing-ctl handling can be exercised without altering PATH.
+var supervisorCtlLookup = exec.LookPath
+
+// isSupervisorCtlAvailable reports whether supervisorctl can be found, so
+// callers can fail clearly instead of letting exec.Command surface a raw
+// "executable file not found" error when only the daemon is installed.
+func isSupervisorCtlAvailable() bool {
+	_, err := supervisorCtlLookup(resolveSupervisorctlBinary())
+	return err == nil
+}
+
+// supervisorGroupProcessNames reads numprocs out of name's .ini config and
+// returns the "group:program_NN" process names supervisord tracks for it.
+func supervisorGroupProcessNames(name string) ([]string, error) {
+	includeDir := resolveManagedSupervisorIncludeDir()
+	f, err := ini.Load(path.Join(includeDir, fmt.Sprintf("%s.ini", name)))
+	if err != nil {
+		return nil, err
+	}
+	section, err := f.GetSection(fmt.Sprintf("program:%s", name))
+	if err != nil {
+		return nil, err
+	}
+	numprocsNum := ""
+	if numprocs, _ := section.GetKey("numprocs"); numprocs != nil {
+		numprocsNum = numprocs.Value()
+	}
+	if numprocsNum == "" {
+		return nil, buserr.New("ErrConfigParse")
+	}
+	return getProcessName(name, numprocsNum), nil
+}
+
+// supervisorProcessPriority returns priority if it's set, or supervisord's
+// own default of 999 otherwise.
+func supervisorProcessPriority(priority *int) int {
+	if priority == nil {
+		return 999
+	}
+	return *priority
+}
+
+// supervisorGroupFilePath returns the path of the config file that tracks a
+// supervisord [group:<group>] section's membership, kept separate from the
+// per-program .ini files so multiple programs can share it.
+func supervisorGroupFilePath(includeDir, group string) string {
+	return path.Join(includeDir, fmt.Sprintf("group_%s.ini", group))
+}
+
+// addSupervisorProcessToGroup adds name to group's programs list, creating
+// the group's config file if this is the first program to join it.
+func addSupervisorProcessToGroup(includeDir, group, name string) error {
+	groupFilePath := supervisorGroupFilePath(includeDir, group)
+	cfg, err := ini.Load(groupFilePath)
+	if err != nil {
+		cfg = ini.Empty()
+	}
+	sectionName := fmt.Sprintf("group:%s", group)
+	section, err := cfg.GetSection(sectionName)
+	if err != nil {
+		if section, err = cfg.NewSection(sectionName); err != nil {
 			return err
 		}
-		section, err := f.GetSection(fmt.Sprintf("program:%s", name))
+	}
+	programs := strings.Split(section.Key("programs").Value(), ",")
+	for _, program := range programs {
+		if strings.TrimSpace(program) == name {
+			return cfg.SaveTo(groupFilePath)
+		}
+	}
+	programs = append(programs, name)
+	var cleaned []string
+	for _, program := range programs {
+		if program = strings.TrimSpace(program); program != "" {
+			cleaned = append(cleaned, program)
+		}
+	}
+	section.Key("programs").SetValue(strings.Join(cleaned, ","))
+	return cfg.SaveTo(groupFilePath)
+}
+
+// supervisorGroupForProcess scans includeDir's group_*.ini files and returns
+// the name of the supervisord group name belongs to, or "" if it isn't in
+// one.
+func supervisorGroupForProcess(includeDir, name string) string {
+	entries, err := os.ReadDir(includeDir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "group_") || !strings.HasSuffix(entry.Name(), ".ini") {
+			continue
+		}
+		cfg, err := ini.Load(path.Join(includeDir, entry.Name()))
 		if err != nil {
-			return err
+			continue
 		}
-		numprocsNum := ""
-		if numprocs, _ := section.GetKey("numprocs"); numprocs != nil {
-			numprocsNum = numprocs.Value()
+		for _, section := range cfg.Sections() {
+			if !strings.HasPrefix(section.Name(), "group:") {
+				continue
+			}
+			for _, program := range strings.Split(section.Key("programs").Value(), ",") {
+				if strings.TrimSpace(program) == name {
+					return strings.TrimPrefix(section.Name(), "group:")
+				}
+			}
 		}
-		if numprocsNum == "" {
-			return buserr.New("ErrConfigParse")
+	}
+	return ""
+}
+
+// defaultSupervisorCtlTimeout bounds how long a single supervisorctl
+// invocation may run before it's killed, so a hung supervisord socket can't
+// block the request goroutine indefinitely. It's a var, not a const, so
+// tests can shrink it rather than actually waiting out the default.
+var defaultSupervisorCtlTimeout = 15 * time.Second
+
+func operateSupervisorCtl(operate, name, group string) error {
+	_, err := supervisorCtlOutput(operate, name, group)
+	return err
+}
+
+// supervisorCtlOutput is operateSupervisorCtl's counterpart for callers that
+// need supervisorctl's stdout rather than just a success/failure result,
+// e.g. parseSupervisorRereadOutput after a "reread".
+func supervisorCtlOutput(operate, name, group string) (string, error) {
+	if !isSupervisorCtlAvailable() {
+		return "", buserr.New("ErrSupervisorCtlMissing")
+	}
+	processNames := []string{operate}
+	if name != "" {
+		names, err := supervisorGroupProcessNames(name)
+		if err != nil {
+			return "", err
 		}
-		processNames = append(processNames, getProcessName(name, numprocsNum)...)
+		processNames = append(processNames, names...)
 	}
 	if group != "" {
 		processNames = append(processNames, group)
 	}
 
-	output, err := exec.Command("supervisorctl", processNames...).Output()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSupervisorCtlTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, resolveSupervisorctlBinary(), processNames...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", buserr.New("ErrSupervisorCtlTimeout")
+	}
+	if err != nil {
+		if output != nil {
+			return "", errors.New(string(output))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// signalSupervisorProcessKill sends SIGKILL to name (and every process in
+// its numprocs group, if any) via `supervisorctl signal KILL`, for a
+// process that ignored a graceful stop and is past its stopwaitsecs grace
+// period.
+func signalSupervisorProcessKill(name string) error {
+	if !isSupervisorCtlAvailable() {
+		return buserr.New("ErrSupervisorCtlMissing")
+	}
+	processNames, err := supervisorGroupProcessNames(name)
+	if err != nil || len(processNames) == 0 {
+		processNames = []string{name}
+	}
+	args := append([]string{"signal", "KILL"}, processNames...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSupervisorCtlTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, resolveSupervisorctlBinary(), args...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return buserr.New("ErrSupervisorCtlTimeout")
+	}
 	if err != nil {
 		if output != nil {
 			return errors.New(string(output))
@@ -516,6 +2212,107 @@ func operateSupervisorCtl(operate, name, group string) error {
 	return nil
 }
 
+// forceStopSupervisorProcess stops name the normal way first, and only
+// escalates to signalSupervisorProcessKill when that fails (e.g. it hung
+// past stopwaitsecs and operateSupervisorCtl's own timeout fired), so a
+// process that does stop cleanly is never killed outright. The bool return
+// reports whether the kill escalation actually ran, for callers that want
+// to log or surface it.
+func forceStopSupervisorProcess(name string) (bool, error) {
+	return stopSupervisorProcess(name, func(n string) error { return operateSupervisorProcessAction(n, false) }, signalSupervisorProcessKill)
+}
+
+func stopSupervisorProcess(name string, stop func(string) error, forceKill func(string) error) (bool, error) {
+	if err := stop(name); err == nil {
+		return false, nil
+	}
+	global.LOG.Warnf("supervisor process %q did not stop gracefully, sending SIGKILL", name)
+	if err := forceKill(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolveSupervisorRPCClient builds an XML-RPC client from the resolved
+// supervisord.conf's [unix_http_server]/[inet_http_server] section. It
+// returns nil when there is no database to read the configured conf path
+// from, when the conf can't be loaded, or when it sets neither
+// [unix_http_server] file= nor [inet_http_server] port=.
+func resolveSupervisorRPCClient() *supervisorrpc.Client {
+	if global.DB == nil {
+		return nil
+	}
+	configPath := "/etc/supervisord.conf"
+	pathSet, _ := settingRepo.Get(settingRepo.WithByKey(constant.SupervisorConfigPath))
+	if pathSet.ID != 0 || pathSet.Value != "" {
+		configPath = pathSet.Value
+	}
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return nil
+	}
+	return supervisorRPCClientFromConfig(cfg)
+}
+
+// supervisorRPCClientFromConfig prefers the unix socket transport when both
+// are configured, since it doesn't depend on a TCP port being reachable.
+func supervisorRPCClientFromConfig(cfg *ini.File) *supervisorrpc.Client {
+	if section, err := cfg.GetSection("unix_http_server"); err == nil {
+		if fileKey, err := section.GetKey("file"); err == nil && fileKey.Value() != "" {
+			username, password := supervisorRPCCredentials(section)
+			return supervisorrpc.NewUnixSocketClient(fileKey.Value(), username, password)
+		}
+	}
+	if section, err := cfg.GetSection("inet_http_server"); err == nil {
+		if portKey, err := section.GetKey("port"); err == nil && portKey.Value() != "" {
+			username, password := supervisorRPCCredentials(section)
+			return supervisorrpc.NewInetClient(portKey.Value(), username, password)
+		}
+	}
+	return nil
+}
+
+func supervisorRPCCredentials(section *ini.Section) (string, string) {
+	username, password := "", ""
+	if key, err := section.GetKey("username"); err == nil {
+		username = key.Value()
+	}
+	if key, err := section.GetKey("password"); err == nil {
+		password = key.Value()
+	}
+	return username, password
+}
+
+// operateSupervisorProcessAction starts or stops every process in the named
+// group, preferring supervisord's XML-RPC interface when it's configured
+// and falling back to supervisorctl when it isn't, or when the RPC call
+// itself fails.
+func operateSupervisorProcessAction(name string, start bool) error {
+	action := "stop"
+	if start {
+		action = "start"
+	}
+	client := resolveSupervisorRPCClient()
+	if client == nil {
+		return operateSupervisorCtl(action, name, "")
+	}
+	processNames, err := supervisorGroupProcessNames(name)
+	if err != nil || len(processNames) == 0 {
+		processNames = []string{name}
+	}
+	for _, processName := range processNames {
+		if start {
+			err = client.StartProcess(processName)
+		} else {
+			err = client.StopProcess(processName)
+		}
+		if err != nil {
+			return operateSupervisorCtl(action, name, "")
+		}
+	}
+	return nil
+}
+
 func getProcessName(name, numprocs string) []string {
 	var (
 		processNames []string
@@ -525,41 +2322,318 @@ func getProcessName(name, numprocs string) []string {
 		return processNames
 	}
 	if num == 1 {
-		processNames = append(processNames, fmt.Sprintf("%s:%s_00", name, name))
-	} else {
-		for i := 0; i < num; i++ {
-			processName := fmt.Sprintf("%s:%s_0%s", name, name, strconv.Itoa(i))
-			if i >= 10 {
-				processName = fmt.Sprintf("%s:%s_%s", name, name, strconv.Itoa(i))
-			}
-			processNames = append(processNames, processName)
-		}
+		return []string{fmt.Sprintf("%s:%s", name, name)}
+	}
+	for i := 0; i < num; i++ {
+		processNames = append(processNames, fmt.Sprintf("%s:%s_%02d", name, name, i))
 	}
 	return processNames
 }
 
+// supervisorProcessName returns the process_name template to write for a
+// program with the given numprocs: supervisord only needs (and conventionally
+// only uses) the %(process_num)s placeholder when there's more than one
+// process instance to disambiguate.
+func supervisorProcessName(numprocs string) string {
+	if num, err := strconv.Atoi(numprocs); err == nil && num > 1 {
+		return "%(program_name)s_%(process_num)02d"
+	}
+	return "%(program_name)s"
+}
+
+// validateNumprocs normalizes and validates a supervisor process's numprocs
+// field before it's written into the ini: empty defaults to "1", and
+// anything that isn't a positive integer is rejected up front instead of
+// producing a broken config that only fails later, at operate time, with
+// ErrConfigParse.
+func validateNumprocs(numprocs string) (string, error) {
+	if numprocs == "" {
+		return "1", nil
+	}
+	num, err := strconv.Atoi(numprocs)
+	if err != nil || num < 1 {
+		return "", buserr.New("ErrInvalidNumprocs")
+	}
+	return numprocs, nil
+}
+
+// applyProcessInfos fills config.Status from an XML-RPC
+// getAllProcessInfo() result, replacing the positional-field parsing of
+// plain-text `supervisorctl status` output.
+func applyProcessInfos(config *response.SupervisorProcessConfig, infos []supervisorrpc.ProcessInfo) {
+	for _, info := range infos {
+		if info.Group != config.Name {
+			continue
+		}
+		status := response.ProcessStatus{Name: info.Name, Status: info.StateName}
+		if info.StateName == "RUNNING" {
+			status.PID = strconv.Itoa(info.PID)
+			status.Uptime = info.Description
+		} else {
+			status.Msg = info.Description
+		}
+		config.Status = append(config.Status, status)
+	}
+}
+
+// supervisorPIDUptimePattern matches the "pid <n>, uptime <t>" fragment
+// supervisorctl appends to a RUNNING status line, wherever it falls in the
+// line. Matching by pattern rather than fixed field indices keeps
+// getProcessStatus working when supervisord's column layout shifts (e.g.
+// a process name containing spaces, or a longer status column).
+var supervisorPIDUptimePattern = regexp.MustCompile(`pid (\d+), uptime (\S+)`)
+
 func getProcessStatus(config *response.SupervisorProcessConfig) error {
+	if client := resolveSupervisorRPCClient(); client != nil {
+		if infos, err := client.GetAllProcessInfo(); err == nil {
+			applyProcessInfos(config, infos)
+			enrichProcessResourceUsage(config.Status)
+			return nil
+		}
+	}
+	if !isSupervisorCtlAvailable() {
+		config.Status = []response.ProcessStatus{{Name: config.Name, Status: "unknown", Msg: "supervisorctl not found"}}
+		return nil
+	}
 	var (
 		processNames = []string{"status"}
 	)
 	processNames = append(processNames, getProcessName(config.Name, config.Numprocs)...)
-	output, _ := exec.Command("supervisorctl", processNames...).Output()
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSupervisorCtlTimeout)
+	defer cancel()
+	output, _ := exec.CommandContext(ctx, resolveSupervisorctlBinary(), processNames...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return buserr.New("ErrSupervisorCtlTimeout")
+	}
+	config.Status = append(config.Status, parseSupervisorCtlStatusOutput(string(output))...)
+	enrichProcessResourceUsage(config.Status)
+	return nil
+}
+
+// procRoot is where enrichProcessResourceUsage looks for a process's /proc
+// entries. It's a var, not a hard-coded "/proc", so tests can point it at a
+// fixture directory instead of the real procfs.
+var procRoot = "/proc"
+
+// clockTicksPerSecond is Linux's USER_HZ (sysconf(_SC_CLK_TCK)), the unit
+// /proc/[pid]/stat's utime/stime/starttime fields are counted in. It's
+// virtually always 100 on Linux across architectures, and there's no way to
+// read it from /proc itself, so it's hard-coded rather than guessed at.
+const clockTicksPerSecond = 100
+
+// enrichProcessResourceUsage populates RSSBytes and CPUPercent on each
+// RUNNING entry of statuses by reading its /proc/<pid>/stat and
+// /proc/<pid>/status -- one entry per pid, so a numprocs>1 group gets
+// per-process figures rather than one summed total. It's a best-effort
+// enrichment: any failure (no /proc, a pid that already exited, a
+// permission error) just leaves that entry's usage fields at zero instead
+// of failing the whole status call.
+func enrichProcessResourceUsage(statuses []response.ProcessStatus) {
+	if _, err := os.Stat(procRoot); err != nil {
+		return
+	}
+	uptimeSeconds, err := readSystemUptimeSeconds()
+	if err != nil {
+		return
+	}
+	for i := range statuses {
+		if statuses[i].Status != "RUNNING" || statuses[i].PID == "" {
+			continue
+		}
+		pid, err := strconv.Atoi(statuses[i].PID)
+		if err != nil {
+			continue
+		}
+		summary, err := readProcStatSummary(pid)
+		if err != nil {
+			continue
+		}
+		statuses[i].RSSBytes = summary.rssKB * 1024
+		statuses[i].CPUPercent = processCPUPercent(summary, uptimeSeconds)
+	}
+}
+
+// procStatSummary is the subset of a process's /proc/<pid>/stat and
+// /proc/<pid>/status that enrichProcessResourceUsage needs.
+type procStatSummary struct {
+	utimeTicks     uint64
+	stimeTicks     uint64
+	starttimeTicks uint64
+	rssKB          uint64
+}
+
+// readProcStatSummary reads and parses pid's /proc/<pid>/stat and
+// /proc/<pid>/status under procRoot.
+func readProcStatSummary(pid int) (procStatSummary, error) {
+	statData, err := os.ReadFile(path.Join(procRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStatSummary{}, err
+	}
+	utime, stime, starttime, err := parseProcStatTimes(string(statData))
+	if err != nil {
+		return procStatSummary{}, err
+	}
+	statusData, err := os.ReadFile(path.Join(procRoot, strconv.Itoa(pid), "status"))
+	if err != nil {
+		return procStatSummary{}, err
+	}
+	rssKB, err := parseProcStatusVMRSS(string(statusData))
+	if err != nil {
+		return procStatSummary{}, err
+	}
+	return procStatSummary{utimeTicks: utime, stimeTicks: stime, starttimeTicks: starttime, rssKB: rssKB}, nil
+}
+
+// parseProcStatTimes extracts the utime (field 14), stime (field 15), and
+// starttime (field 22) columns from a /proc/[pid]/stat line. It locates
+// them by skipping past the comm field's closing parenthesis rather than
+// splitting on whitespace from the start, since comm (the process name, in
+// parens) can itself contain spaces and parentheses.
+func parseProcStatTimes(line string) (utime, stime, starttime uint64, err error) {
+	line = strings.TrimSpace(line)
+	closeParen := strings.LastIndex(line, ")")
+	if closeParen == -1 || closeParen+2 > len(line) {
+		return 0, 0, 0, fmt.Errorf("malformed /proc stat line")
+	}
+	// fields[0] is field 3 (state); field N is at fields[N-3].
+	fields := strings.Fields(line[closeParen+2:])
+	field := func(n int) (uint64, error) {
+		idx := n - 3
+		if idx < 0 || idx >= len(fields) {
+			return 0, fmt.Errorf("missing /proc stat field %d", n)
+		}
+		return strconv.ParseUint(fields[idx], 10, 64)
+	}
+	if utime, err = field(14); err != nil {
+		return 0, 0, 0, err
+	}
+	if stime, err = field(15); err != nil {
+		return 0, 0, 0, err
+	}
+	if starttime, err = field(22); err != nil {
+		return 0, 0, 0, err
+	}
+	return utime, stime, starttime, nil
+}
+
+// parseProcStatusVMRSS extracts the resident set size, in KB, from a
+// /proc/[pid]/status file's "VmRSS:" line.
+func parseProcStatusVMRSS(content string) (uint64, error) {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
 		fields := strings.Fields(line)
-		if len(fields) >= 5 {
-			status := response.ProcessStatus{
-				Name:   fields[0],
-				Status: fields[1],
-			}
-			if fields[1] == "RUNNING" {
-				status.PID = strings.TrimSuffix(fields[3], ",")
-				status.Uptime = fields[5]
-			} else {
-				status.Msg = strings.Join(fields[2:], " ")
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("malformed VmRSS line: %q", line)
+		}
+		return strconv.ParseUint(fields[1], 10, 64)
+	}
+	return 0, fmt.Errorf("VmRSS not found")
+}
+
+// readSystemUptimeSeconds reads the system uptime, in seconds, from
+// /proc/uptime under procRoot.
+func readSystemUptimeSeconds() (float64, error) {
+	data, err := os.ReadFile(path.Join(procRoot, "uptime"))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty /proc/uptime")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// processCPUPercent averages a process's total CPU time over its age (wall
+// clock time since it started), both derived from summary's ticks and the
+// current system uptime. It's a lifetime average, not an instantaneous
+// rate: a true instantaneous rate needs two samples spaced apart, which a
+// single /proc read can't provide.
+func processCPUPercent(summary procStatSummary, systemUptimeSeconds float64) float64 {
+	ageSeconds := systemUptimeSeconds - float64(summary.starttimeTicks)/clockTicksPerSecond
+	if ageSeconds <= 0 {
+		return 0
+	}
+	cpuSeconds := float64(summary.utimeTicks+summary.stimeTicks) / clockTicksPerSecond
+	return cpuSeconds / ageSeconds * 100
+}
+
+// parseSupervisorCtlStatusOutput parses the lines `supervisorctl status`
+// prints per process. It matches the PID/uptime fragment of a RUNNING line
+// by pattern rather than fixed field indices, so it doesn't panic or
+// misparse on short lines, process names containing spaces, or a status
+// column that shifted (e.g. STARTING, BACKOFF).
+func parseSupervisorCtlStatusOutput(output string) []response.ProcessStatus {
+	var result []response.ProcessStatus
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		status := response.ProcessStatus{
+			Name:   fields[0],
+			Status: fields[1],
+		}
+		if fields[1] == "RUNNING" {
+			if m := supervisorPIDUptimePattern.FindStringSubmatch(line); m != nil {
+				status.PID = m[1]
+				status.Uptime = m[2]
 			}
-			config.Status = append(config.Status, status)
+		} else if len(fields) > 2 {
+			status.Msg = strings.Join(fields[2:], " ")
 		}
+		result = append(result, status)
 	}
-	return nil
+	return result
+}
+
+// getProcessLogStatus stats the stdout/stderr log files configured for a
+// supervisor program and reports their size against the configured
+// `*_logfile_maxbytes`/`*_logfile_backups` rotation settings.
+func getProcessLogStatus(section *ini.Section) []response.LogFileStatus {
+	var result []response.LogFileStatus
+	for _, prefix := range []string{"stdout", "stderr"} {
+		logfileKey, err := section.GetKey(fmt.Sprintf("%s_logfile", prefix))
+		if err != nil || logfileKey.Value() == "" {
+			continue
+		}
+		logfile := logfileKey.Value()
+		info, err := os.Stat(logfile)
+		if err != nil {
+			continue
+		}
+		status := response.LogFileStatus{File: logfile, Size: info.Size()}
+		if maxbytesKey, err := section.GetKey(fmt.Sprintf("%s_logfile_maxbytes", prefix)); err == nil {
+			status.MaxBytes = parseSupervisorByteSize(maxbytesKey.Value())
+		}
+		if backupsKey, err := section.GetKey(fmt.Sprintf("%s_logfile_backups", prefix)); err == nil {
+			status.Backups, _ = strconv.Atoi(backupsKey.Value())
+		}
+		if status.MaxBytes > 0 && float64(status.Size) >= float64(status.MaxBytes)*nearCapRatio {
+			status.NearCap = true
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// parseSupervisorByteSize parses the byte-size suffixes supervisord accepts
+// for `*_logfile_maxbytes` (e.g. "2MB", "1GB", or a plain byte count).
+func parseSupervisorByteSize(value string) int64 {
+	value = strings.TrimSpace(value)
+	units := map[string]int64{"KB": 1024, "MB": 1024 * 1024, "GB": 1024 * 1024 * 1024}
+	for suffix, multiplier := range units {
+		if strings.HasSuffix(strings.ToUpper(value), suffix) {
+			num := strings.TrimSpace(value[:len(value)-len(suffix)])
+			n, err := strconv.ParseInt(num, 10, 64)
+			if err != nil {
+				return 0
+			}
+			return n * multiplier
+		}
+	}
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
 }
@@ -0,0 +1,2133 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1Panel-dev/1Panel/backend/app/dto/request"
+	"github.com/1Panel-dev/1Panel/backend/app/dto/response"
+	"github.com/1Panel-dev/1Panel/backend/app/model"
+	"github.com/1Panel-dev/1Panel/backend/app/repo"
+	"github.com/1Panel-dev/1Panel/backend/buserr"
+	"github.com/1Panel-dev/1Panel/backend/constant"
+	"github.com/1Panel-dev/1Panel/backend/global"
+	"github.com/1Panel-dev/1Panel/backend/utils/files"
+	"github.com/1Panel-dev/1Panel/backend/utils/supervisorrpc"
+	"gopkg.in/ini.v1"
+	"gorm.io/gorm"
+)
+
+func TestCountSupervisordPIDs(t *testing.T) {
+	cases := map[string]int{
+		"":             0,
+		"1234\n":       1,
+		"1234\n5678\n": 2,
+	}
+	for out, want := range cases {
+		if got := countSupervisordPIDs(out); got != want {
+			t.Errorf("countSupervisordPIDs(%q) = %d, want %d", out, got, want)
+		}
+	}
+}
+
+func TestGetProcessName(t *testing.T) {
+	cases := map[string][]string{
+		"1":  {"app:app"},
+		"9":  {"app:app_00", "app:app_01", "app:app_02", "app:app_03", "app:app_04", "app:app_05", "app:app_06", "app:app_07", "app:app_08"},
+		"10": {"app:app_00", "app:app_01", "app:app_02", "app:app_03", "app:app_04", "app:app_05", "app:app_06", "app:app_07", "app:app_08", "app:app_09"},
+	}
+	for numprocs, expected := range cases {
+		got := getProcessName("app", numprocs)
+		if len(got) != len(expected) {
+			t.Fatalf("numprocs=%s: expected %d names, got %d (%v)", numprocs, len(expected), len(got), got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("numprocs=%s: name %d = %q, want %q", numprocs, i, got[i], expected[i])
+			}
+		}
+	}
+
+	got := getProcessName("app", "25")
+	if len(got) != 25 {
+		t.Fatalf("expected 25 names, got %d", len(got))
+	}
+	if got[9] != "app:app_09" || got[10] != "app:app_10" || got[24] != "app:app_24" {
+		t.Fatalf("unexpected names around the 10s boundary: %v", got[8:11])
+	}
+}
+
+func TestStreamSupervisorProcessLogPlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := StreamSupervisorProcessLog(&out, strings.NewReader("hello log"), false); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello log" {
+		t.Fatalf("expected output to match input unchanged, got %q", out.String())
+	}
+}
+
+func TestStreamSupervisorProcessLogGzip(t *testing.T) {
+	var out bytes.Buffer
+	if err := StreamSupervisorProcessLog(&out, strings.NewReader("hello log"), true); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&out)
+	if err != nil {
+		t.Fatalf("expected gzip-compressed output, err: %v", err)
+	}
+	defer gz.Close()
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "hello log" {
+		t.Fatalf("expected decompressed output to match input, got %q", content)
+	}
+}
+
+func TestParseSupervisorByteSize(t *testing.T) {
+	cases := map[string]int64{
+		"2MB":   2 * 1024 * 1024,
+		"1GB":   1024 * 1024 * 1024,
+		"512KB": 512 * 1024,
+		"100":   100,
+		"":      0,
+	}
+	for value, expected := range cases {
+		if got := parseSupervisorByteSize(value); got != expected {
+			t.Errorf("parseSupervisorByteSize(%q) = %d, want %d", value, got, expected)
+		}
+	}
+}
+
+func TestGetProcessLogStatusNearCap(t *testing.T) {
+	logfile := t.TempDir() + "/app.out.log"
+	content := make([]byte, 950)
+	if err := os.WriteFile(logfile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("program:app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("stdout_logfile", logfile)
+	_, _ = section.NewKey("stdout_logfile_maxbytes", "1000")
+	_, _ = section.NewKey("stdout_logfile_backups", "5")
+
+	statuses := getProcessLogStatus(section)
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 log status, got %d", len(statuses))
+	}
+	if statuses[0].Size != 950 || statuses[0].MaxBytes != 1000 || statuses[0].Backups != 5 {
+		t.Fatalf("unexpected log status: %+v", statuses[0])
+	}
+	if !statuses[0].NearCap {
+		t.Fatal("expected log file at 95%% of cap to be flagged as near cap")
+	}
+}
+
+func TestRestoreSupervisorIncludeRestoresMatchingBackup(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("files", "/opt/1panel/supervisor.d/*.ini")
+	_, _ = section.NewKey(";files", "/etc/supervisor/conf.d/*.conf")
+
+	restored, err := restoreSupervisorInclude(cfg, "/opt/1panel/supervisor.d/*.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored {
+		t.Fatal("expected the backup to be restored")
+	}
+	filesKey, _ := section.GetKey("files")
+	if filesKey.Value() != "/etc/supervisor/conf.d/*.conf" {
+		t.Fatalf("expected files to be restored to the backup value, got %q", filesKey.Value())
+	}
+	if section.HasKey(";files") {
+		t.Fatal("expected the backup key to be removed after restoring")
+	}
+}
+
+func TestRestoreSupervisorIncludeSkipsWhenValueWasChanged(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("files", "/some/other/path/*.ini")
+	_, _ = section.NewKey(";files", "/etc/supervisor/conf.d/*.conf")
+
+	restored, err := restoreSupervisorInclude(cfg, "/opt/1panel/supervisor.d/*.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatal("expected restoration to be skipped when files no longer matches what we wrote")
+	}
+	filesKey, _ := section.GetKey("files")
+	if filesKey.Value() != "/some/other/path/*.ini" {
+		t.Fatal("expected the unmatched value to be left untouched")
+	}
+}
+
+func withMissingSupervisorCtl(t *testing.T) {
+	t.Helper()
+	orig := supervisorCtlLookup
+	supervisorCtlLookup = func(string) (string, error) { return "", errors.New("not found") }
+	t.Cleanup(func() { supervisorCtlLookup = orig })
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+}
+
+func TestOperateSupervisorCtlReturnsClearErrorWhenMissing(t *testing.T) {
+	withMissingSupervisorCtl(t)
+
+	err := operateSupervisorCtl("status", "", "")
+	if err == nil {
+		t.Fatal("expected an error when supervisorctl is missing")
+	}
+}
+
+// withFakeSupervisorCtl puts a shell script named "supervisorctl" ahead of
+// the real one on PATH, and makes isSupervisorCtlAvailable report it as
+// found, so operateSupervisorCtl/getProcessStatus actually run it.
+func withFakeSupervisorCtl(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := dir + "/supervisorctl"
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexec "+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	origLookup := supervisorCtlLookup
+	supervisorCtlLookup = func(string) (string, error) { return path, nil }
+	t.Cleanup(func() { supervisorCtlLookup = origLookup })
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origPath := os.Getenv("PATH")
+	_ = os.Setenv("PATH", dir+string(os.PathListSeparator)+origPath)
+	t.Cleanup(func() { _ = os.Setenv("PATH", origPath) })
+
+	origTimeout := defaultSupervisorCtlTimeout
+	defaultSupervisorCtlTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { defaultSupervisorCtlTimeout = origTimeout })
+}
+
+func assertSupervisorCtlTimeoutError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected a timeout error, got none")
+	}
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrSupervisorCtlTimeout" {
+		t.Fatalf("expected ErrSupervisorCtlTimeout, got %#v", err)
+	}
+}
+
+func TestOperateSupervisorCtlReturnsTimeoutWhenHung(t *testing.T) {
+	withFakeSupervisorCtl(t, "sleep 5")
+
+	assertSupervisorCtlTimeoutError(t, operateSupervisorCtl("status", "", ""))
+}
+
+func TestGetProcessStatusReturnsTimeoutWhenHung(t *testing.T) {
+	withFakeSupervisorCtl(t, "sleep 5")
+
+	config := &response.SupervisorProcessConfig{Name: "app", Numprocs: "1"}
+	assertSupervisorCtlTimeoutError(t, getProcessStatus(config))
+}
+
+func TestGetProcessStatusReportsUnknownWhenCtlMissing(t *testing.T) {
+	withMissingSupervisorCtl(t)
+
+	config := &response.SupervisorProcessConfig{Name: "app", Numprocs: "1"}
+	if err := getProcessStatus(config); err != nil {
+		t.Fatalf("expected no error so the list view doesn't break, got %v", err)
+	}
+	if len(config.Status) != 1 || config.Status[0].Status != "unknown" {
+		t.Fatalf("expected a single unknown status entry, got %+v", config.Status)
+	}
+}
+
+func writeTestFile(t *testing.T, content string) string {
+	t.Helper()
+	path := t.TempDir() + "/test.log"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestViewConfigReturnsWholeFileByDefault(t *testing.T) {
+	path := writeTestFile(t, "line1\nline2\nline3")
+
+	got, err := ViewConfig(path, request.ConfigOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "line1\nline2\nline3" {
+		t.Fatalf("expected the whole file, got %q", got)
+	}
+}
+
+func TestViewConfigHeadLines(t *testing.T) {
+	path := writeTestFile(t, "line1\nline2\nline3\nline4")
+
+	got, err := ViewConfig(path, request.ConfigOption{HeadLines: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "line1\nline2" {
+		t.Fatalf("expected the first 2 lines, got %q", got)
+	}
+}
+
+func TestViewConfigTailLines(t *testing.T) {
+	path := writeTestFile(t, "line1\nline2\nline3\nline4")
+
+	got, err := ViewConfig(path, request.ConfigOption{TailLines: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "line3\nline4" {
+		t.Fatalf("expected the last 2 lines, got %q", got)
+	}
+}
+
+func TestViewConfigGrepFiltersMatchingLines(t *testing.T) {
+	path := writeTestFile(t, "INFO starting\nERROR boom\nINFO done\nERROR again")
+
+	got, err := ViewConfig(path, request.ConfigOption{Grep: "^ERROR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ERROR boom\nERROR again" {
+		t.Fatalf("expected only ERROR lines, got %q", got)
+	}
+}
+
+func TestViewConfigGrepEmptyMatch(t *testing.T) {
+	path := writeTestFile(t, "INFO starting\nINFO done")
+
+	got, err := ViewConfig(path, request.ConfigOption{Grep: "ERROR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Fatalf("expected no matches to produce an empty result, got %q", got)
+	}
+}
+
+func TestViewConfigGrepCombinedWithTail(t *testing.T) {
+	path := writeTestFile(t, "ERROR one\nINFO two\nERROR three\nINFO four")
+
+	got, err := ViewConfig(path, request.ConfigOption{TailLines: 2, Grep: "ERROR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ERROR three" {
+		t.Fatalf("expected the tail window filtered by grep, got %q", got)
+	}
+}
+
+func TestViewConfigInvalidGrepPattern(t *testing.T) {
+	path := writeTestFile(t, "line1")
+
+	if _, err := ViewConfig(path, request.ConfigOption{Grep: "("}); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
+
+func TestSupervisorRPCClientFromConfigPrefersUnixSocket(t *testing.T) {
+	cfg := ini.Empty()
+	unixSection, err := cfg.NewSection("unix_http_server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = unixSection.NewKey("file", "/var/run/supervisor.sock")
+	inetSection, err := cfg.NewSection("inet_http_server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = inetSection.NewKey("port", "127.0.0.1:9001")
+
+	client := supervisorRPCClientFromConfig(cfg)
+	if client == nil {
+		t.Fatal("expected a client to be built from the unix_http_server section")
+	}
+}
+
+func TestSupervisorRPCClientFromConfigFallsBackToInet(t *testing.T) {
+	cfg := ini.Empty()
+	inetSection, err := cfg.NewSection("inet_http_server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = inetSection.NewKey("port", "127.0.0.1:9001")
+
+	client := supervisorRPCClientFromConfig(cfg)
+	if client == nil {
+		t.Fatal("expected a client to be built from the inet_http_server section")
+	}
+}
+
+func TestSupervisorRPCClientFromConfigNoneConfigured(t *testing.T) {
+	cfg := ini.Empty()
+
+	if client := supervisorRPCClientFromConfig(cfg); client != nil {
+		t.Fatal("expected no client when neither server section is configured")
+	}
+}
+
+func TestApplyProcessInfosFiltersByGroupAndFormatsRunning(t *testing.T) {
+	config := &response.SupervisorProcessConfig{Name: "app"}
+	applyProcessInfos(config, []supervisorrpc.ProcessInfo{
+		{Name: "app_00", Group: "app", StateName: "RUNNING", PID: 1234, Description: "pid 1234, uptime 0:01:00"},
+		{Name: "other_00", Group: "other", StateName: "RUNNING", PID: 5678},
+		{Name: "app_01", Group: "app", StateName: "STOPPED", Description: "Not started"},
+	})
+
+	if len(config.Status) != 2 {
+		t.Fatalf("expected only the two \"app\" processes, got %+v", config.Status)
+	}
+	if config.Status[0].PID != "1234" || config.Status[0].Uptime != "pid 1234, uptime 0:01:00" {
+		t.Fatalf("unexpected running status: %+v", config.Status[0])
+	}
+	if config.Status[1].Msg != "Not started" {
+		t.Fatalf("unexpected stopped status: %+v", config.Status[1])
+	}
+}
+
+func TestSupervisorIncludeDirFromConfigUsesCustomGlob(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("files", "/etc/supervisor/conf.d/*.conf")
+
+	dir := supervisorIncludeDirFromConfig(cfg, "/opt/1panel/tools/supervisord/supervisor.d")
+	if dir != "/etc/supervisor/conf.d" {
+		t.Fatalf("expected the custom include dir, got %q", dir)
+	}
+}
+
+func TestSupervisorIncludeDirFromConfigFallsBackWithoutInclude(t *testing.T) {
+	cfg := ini.Empty()
+
+	dir := supervisorIncludeDirFromConfig(cfg, "/opt/1panel/tools/supervisord/supervisor.d")
+	if dir != "/opt/1panel/tools/supervisord/supervisor.d" {
+		t.Fatalf("expected the managed dir fallback, got %q", dir)
+	}
+}
+
+func TestRestoreSupervisorIncludeNoBackup(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("files", "/opt/1panel/supervisor.d/*.ini")
+
+	restored, err := restoreSupervisorInclude(cfg, "/opt/1panel/supervisor.d/*.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatal("expected no restoration when there is no backup key")
+	}
+}
+
+func TestAddSupervisorProcessToGroupCreatesSection(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := addSupervisorProcessToGroup(dir, "web", "app1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := addSupervisorProcessToGroup(dir, "web", "app2"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ini.Load(supervisorGroupFilePath(dir, "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	section, err := cfg.GetSection("group:web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if programs := section.Key("programs").Value(); programs != "app1,app2" {
+		t.Fatalf("expected both programs to be listed, got %q", programs)
+	}
+}
+
+func TestAddSupervisorProcessToGroupIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := addSupervisorProcessToGroup(dir, "web", "app1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := addSupervisorProcessToGroup(dir, "web", "app1"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ini.Load(supervisorGroupFilePath(dir, "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	section, err := cfg.GetSection("group:web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if programs := section.Key("programs").Value(); programs != "app1" {
+		t.Fatalf("expected the program to be listed only once, got %q", programs)
+	}
+}
+
+func TestSupervisorGroupForProcessFindsMembership(t *testing.T) {
+	dir := t.TempDir()
+	if err := addSupervisorProcessToGroup(dir, "web", "app1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := addSupervisorProcessToGroup(dir, "web", "app2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if group := supervisorGroupForProcess(dir, "app2"); group != "web" {
+		t.Fatalf("expected app2 to be found in group 'web', got %q", group)
+	}
+	if group := supervisorGroupForProcess(dir, "app3"); group != "" {
+		t.Fatalf("expected app3 to belong to no group, got %q", group)
+	}
+}
+
+func TestResolveSupervisordLogPathPlainFile(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("supervisord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("logfile", "/var/log/supervisord.log")
+
+	path, source, err := resolveSupervisordLogPath(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/var/log/supervisord.log" || source != "file" {
+		t.Fatalf("expected the configured file path, got path=%q source=%q", path, source)
+	}
+}
+
+func TestResolveSupervisordLogPathAutoFallsBackToDefault(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("supervisord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("logfile", "AUTO")
+
+	path, source, err := resolveSupervisordLogPath(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != defaultSupervisordLogPath || source != "file" {
+		t.Fatalf("expected the default log path for AUTO, got path=%q source=%q", path, source)
+	}
+}
+
+func TestResolveSupervisordLogPathSyslogIsNotAFile(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("supervisord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("logfile", "syslog")
+
+	path, source, err := resolveSupervisordLogPath(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != "syslog" || path == "" {
+		t.Fatalf("expected a non-file syslog source, got path=%q source=%q", path, source)
+	}
+}
+
+func TestResolveSupervisordLogPathMissingKeyFallsBackToDefault(t *testing.T) {
+	cfg := ini.Empty()
+	if _, err := cfg.NewSection("supervisord"); err != nil {
+		t.Fatal(err)
+	}
+
+	path, source, err := resolveSupervisordLogPath(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != defaultSupervisordLogPath || source != "file" {
+		t.Fatalf("expected the default log path when logfile is unset, got path=%q source=%q", path, source)
+	}
+}
+
+func TestValidateNumprocsDefaultsEmptyToOne(t *testing.T) {
+	numprocs, err := validateNumprocs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numprocs != "1" {
+		t.Fatalf("expected empty numprocs to default to \"1\", got %q", numprocs)
+	}
+}
+
+func TestValidateNumprocsRejectsZero(t *testing.T) {
+	if _, err := validateNumprocs("0"); err == nil {
+		t.Fatal("expected \"0\" to be rejected")
+	}
+}
+
+func TestValidateNumprocsRejectsNonNumeric(t *testing.T) {
+	if _, err := validateNumprocs("abc"); err == nil {
+		t.Fatal("expected \"abc\" to be rejected")
+	}
+}
+
+func TestValidateNumprocsAcceptsPositiveInteger(t *testing.T) {
+	numprocs, err := validateNumprocs("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numprocs != "4" {
+		t.Fatalf("expected \"4\" to be preserved, got %q", numprocs)
+	}
+}
+
+func TestValidateSupervisordSettingsRejectsInvalidLoglevel(t *testing.T) {
+	err := validateSupervisordSettings(request.SupervisordSettings{Loglevel: "verbose"})
+	if err == nil {
+		t.Fatal("expected an invalid loglevel to be rejected")
+	}
+}
+
+func TestValidateSupervisordSettingsAcceptsKnownLoglevel(t *testing.T) {
+	if err := validateSupervisordSettings(request.SupervisordSettings{Loglevel: "debug"}); err != nil {
+		t.Fatalf("expected \"debug\" to be accepted, got %v", err)
+	}
+}
+
+func TestValidateSupervisordSettingsRejectsNonNumericMinfds(t *testing.T) {
+	err := validateSupervisordSettings(request.SupervisordSettings{Minfds: "abc"})
+	if err == nil {
+		t.Fatal("expected a non-numeric minfds to be rejected")
+	}
+}
+
+func TestApplySupervisordSettingsPreservesOtherKeysAndSections(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("supervisord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = section.NewKey("logfile", "/var/log/supervisor/supervisord.log")
+	_, _ = section.NewKey("nodaemon", "false")
+	if _, err := cfg.NewSection("include"); err != nil {
+		t.Fatal(err)
+	}
+
+	applySupervisordSettings(cfg, request.SupervisordSettings{Loglevel: "debug"})
+
+	got := loadSupervisordSettings(cfg)
+	if got.Loglevel != "debug" {
+		t.Fatalf("expected loglevel to be set to \"debug\", got %q", got.Loglevel)
+	}
+	if got.Logfile != "/var/log/supervisor/supervisord.log" {
+		t.Fatalf("expected logfile to be left untouched, got %q", got.Logfile)
+	}
+	if nodaemon := cfg.Section("supervisord").Key("nodaemon").Value(); nodaemon != "false" {
+		t.Fatalf("expected nodaemon to be left untouched, got %q", nodaemon)
+	}
+	if _, err := cfg.GetSection("include"); err != nil {
+		t.Fatal("expected the include section to be left untouched")
+	}
+}
+
+func TestSupervisorProcessNameOmitsIndexForSingleProcess(t *testing.T) {
+	if name := supervisorProcessName("1"); name != "%(program_name)s" {
+		t.Fatalf("expected no index placeholder for numprocs=1, got %q", name)
+	}
+}
+
+func TestSupervisorProcessNameIncludesIndexForMultipleProcesses(t *testing.T) {
+	if name := supervisorProcessName("4"); name != "%(program_name)s_%(process_num)02d" {
+		t.Fatalf("expected an index placeholder for numprocs=4, got %q", name)
+	}
+}
+
+func TestFollowLogFileStreamsAppendedData(t *testing.T) {
+	path := writeTestFile(t, "line1\n")
+
+	closeCh := make(chan struct{})
+	var mu sync.Mutex
+	var received []byte
+	done := make(chan error, 1)
+	go func() {
+		done <- followLogFile(path, closeCh, func(data []byte) error {
+			mu.Lock()
+			received = append(received, data...)
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("line2\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		mu.Lock()
+		gotAll := strings.Contains(string(received), "line2")
+		mu.Unlock()
+		if gotAll {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for appended data to be followed")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	close(closeCh)
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean return on close, got %v", err)
+	}
+}
+
+func TestFollowLogFileReopensOnTruncate(t *testing.T) {
+	path := writeTestFile(t, "before-rotation\n")
+
+	closeCh := make(chan struct{})
+	var mu sync.Mutex
+	var received []byte
+	done := make(chan error, 1)
+	go func() {
+		done <- followLogFile(path, closeCh, func(data []byte) error {
+			mu.Lock()
+			received = append(received, data...)
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("after-rotation\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		mu.Lock()
+		gotAll := strings.Contains(string(received), "after-rotation")
+		mu.Unlock()
+		if gotAll {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for the reopened file's content")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	close(closeCh)
+	<-done
+}
+
+func TestFollowLogFileReturnsOnClose(t *testing.T) {
+	path := writeTestFile(t, "")
+	closeCh := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- followLogFile(path, closeCh, func([]byte) error { return nil })
+	}()
+	close(closeCh)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil error on close, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected followLogFile to return promptly after close")
+	}
+}
+
+func TestFollowSupervisorProcessLogRejectsOverCap(t *testing.T) {
+	supervisorLogFollowers.Store(maxSupervisorLogFollowers)
+	defer supervisorLogFollowers.Store(0)
+
+	h := &HostToolService{}
+	err := h.FollowSupervisorProcessLog(nil, request.SupervisorProcessFileReq{Name: "app", File: "out.log"})
+	if err == nil {
+		t.Fatal("expected an error once the follower cap is reached")
+	}
+	if supervisorLogFollowers.Load() != maxSupervisorLogFollowers {
+		t.Fatalf("expected the follower count to be restored after rejecting, got %d", supervisorLogFollowers.Load())
+	}
+}
+
+func TestSupervisorProcessPriorityDefaultsTo999(t *testing.T) {
+	if priority := supervisorProcessPriority(nil); priority != 999 {
+		t.Fatalf("expected the default priority of 999, got %d", priority)
+	}
+	custom := 10
+	if priority := supervisorProcessPriority(&custom); priority != 10 {
+		t.Fatalf("expected the custom priority to be preserved, got %d", priority)
+	}
+}
+
+// fakeSettingRepoWithNoCustomCommands answers every Get with a zero-value
+// Setting so loadServiceCustomCommands short-circuits to an empty map
+// without touching global.DB.
+type fakeSettingRepoWithNoCustomCommands struct {
+	repo.ISettingRepo
+}
+
+func (fakeSettingRepoWithNoCustomCommands) Get(opts ...repo.DBOption) (model.Setting, error) {
+	return model.Setting{}, nil
+}
+
+func (fakeSettingRepoWithNoCustomCommands) WithByKey(key string) repo.DBOption {
+	return func(db *gorm.DB) *gorm.DB { return db }
+}
+
+// withStubbedServiceAction overrides the DI points OperateTool's audit
+// logging goes through so it can be exercised without a live database or
+// init system: settingRepo (loadServiceCustomCommands), serviceOperate (the
+// actual start/stop/restart call) and the two service action log hooks.
+func withStubbedServiceAction(t *testing.T, operateErr error) *[]model.ServiceActionLog {
+	t.Helper()
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origOperate := serviceOperate
+	serviceOperate = func(customCommands map[string]map[string]string, operate, serviceName, manager string) error {
+		return operateErr
+	}
+	t.Cleanup(func() { serviceOperate = origOperate })
+
+	recorded := &[]model.ServiceActionLog{}
+	origCreate := createServiceActionLog
+	createServiceActionLog = func(log *model.ServiceActionLog) error {
+		*recorded = append(*recorded, *log)
+		return nil
+	}
+	t.Cleanup(func() { createServiceActionLog = origCreate })
+
+	origPrune := pruneServiceActionLogs
+	pruneServiceActionLogs = func(keep int) error { return nil }
+	t.Cleanup(func() { pruneServiceActionLogs = origPrune })
+
+	return recorded
+}
+
+func TestOperateToolRecordsOneSuccessfulServiceActionLog(t *testing.T) {
+	recorded := withStubbedServiceAction(t, nil)
+
+	h := &HostToolService{}
+	req := request.HostToolReq{Type: "nginx", Operate: "restart", Manager: "systemd"}
+	if _, err := h.OperateTool(req, "127.0.0.1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(*recorded) != 1 {
+		t.Fatalf("expected exactly one audit record, got %d", len(*recorded))
+	}
+	log := (*recorded)[0]
+	if log.Operator != "127.0.0.1" || log.ServiceName != "nginx" || log.Action != "restart" || log.Result != "success" {
+		t.Fatalf("unexpected audit record: %+v", log)
+	}
+}
+
+func TestOperateToolEnableCallsManagerWithEnableAction(t *testing.T) {
+	withStubbedServiceAction(t, nil)
+
+	var gotAction, gotService, gotManager string
+	origOperate := serviceOperate
+	serviceOperate = func(customCommands map[string]map[string]string, operate, serviceName, manager string) error {
+		gotAction, gotService, gotManager = operate, serviceName, manager
+		return nil
+	}
+	t.Cleanup(func() { serviceOperate = origOperate })
+
+	h := &HostToolService{}
+	req := request.HostToolReq{Type: "nginx", Operate: "enable", Manager: "systemd"}
+	if _, err := h.OperateTool(req, "127.0.0.1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotAction != "enable" || gotService != "nginx" || gotManager != "systemd" {
+		t.Fatalf("unexpected manager call: action=%q service=%q manager=%q", gotAction, gotService, gotManager)
+	}
+}
+
+func TestOperateToolDisableCallsManagerWithDisableAction(t *testing.T) {
+	withStubbedServiceAction(t, nil)
+
+	var gotAction, gotService, gotManager string
+	origOperate := serviceOperate
+	serviceOperate = func(customCommands map[string]map[string]string, operate, serviceName, manager string) error {
+		gotAction, gotService, gotManager = operate, serviceName, manager
+		return nil
+	}
+	t.Cleanup(func() { serviceOperate = origOperate })
+
+	h := &HostToolService{}
+	req := request.HostToolReq{Type: "nginx", Operate: "disable", Manager: "systemd"}
+	if _, err := h.OperateTool(req, "127.0.0.1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotAction != "disable" || gotService != "nginx" || gotManager != "systemd" {
+		t.Fatalf("unexpected manager call: action=%q service=%q manager=%q", gotAction, gotService, gotManager)
+	}
+}
+
+func TestParseImportedSupervisorConfigAcceptsSingleProgramSection(t *testing.T) {
+	content := "[program:myapp]\ncommand=/bin/myapp\nstdout_logfile=/var/log/myapp.out.log\n"
+	name, cfg, section, err := parseImportedSupervisorConfig(content)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if name != "myapp" {
+		t.Fatalf("expected name %q, got %q", "myapp", name)
+	}
+	if cfg == nil || section == nil {
+		t.Fatal("expected a non-nil config and section")
+	}
+	if section.Key("command").Value() != "/bin/myapp" {
+		t.Fatalf("unexpected command value: %q", section.Key("command").Value())
+	}
+}
+
+func TestParseImportedSupervisorConfigRejectsMultipleProgramSections(t *testing.T) {
+	content := "[program:a]\ncommand=/bin/a\n[program:b]\ncommand=/bin/b\n"
+	if _, _, _, err := parseImportedSupervisorConfig(content); err == nil {
+		t.Fatal("expected an error for a config with more than one program section")
+	}
+}
+
+func TestParseImportedSupervisorConfigRejectsMissingProgramSection(t *testing.T) {
+	content := "[supervisord]\nlogfile=/var/log/supervisord.log\n"
+	if _, _, _, err := parseImportedSupervisorConfig(content); err == nil {
+		t.Fatal("expected an error for a config with no program section")
+	}
+}
+
+func TestParseImportedSupervisorConfigRejectsMalformedIni(t *testing.T) {
+	content := "this is not valid ini [["
+	if _, _, _, err := parseImportedSupervisorConfig(content); err == nil {
+		t.Fatal("expected an error for malformed ini content")
+	}
+}
+
+func TestNormalizeImportedLogPathsRewritesOnlySetKeys(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("program:myapp")
+	if err != nil {
+		t.Fatalf("failed to build section: %v", err)
+	}
+	_, _ = section.NewKey("stdout_logfile", "/wherever/myapp.out.log")
+
+	normalizeImportedLogPaths(section, "/managed/log", "myapp")
+
+	if got := section.Key("stdout_logfile").Value(); got != "/managed/log/myapp.out.log" {
+		t.Fatalf("expected stdout_logfile to be rewritten, got %q", got)
+	}
+	if got := section.Key("stderr_logfile").Value(); got != "" {
+		t.Fatalf("expected stderr_logfile to remain unset, got %q", got)
+	}
+}
+
+func TestExportAndImportSupervisorProcessConfigsRoundTrip(t *testing.T) {
+	withFakeSupervisorCtl(t, "true")
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origBaseDir := global.CONF.System.BaseDir
+	global.CONF.System.BaseDir = t.TempDir()
+	t.Cleanup(func() { global.CONF.System.BaseDir = origBaseDir })
+
+	includeDir := resolveSupervisorIncludeDir()
+	if err := os.MkdirAll(includeDir, 0755); err != nil {
+		t.Fatalf("failed to create include dir: %v", err)
+	}
+
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("program:app1")
+	if err != nil {
+		t.Fatalf("failed to build fixture section: %v", err)
+	}
+	_, _ = section.NewKey("command", "/bin/app1")
+	if err := cfg.SaveTo(path.Join(includeDir, "app1.ini")); err != nil {
+		t.Fatalf("failed to write fixture ini: %v", err)
+	}
+
+	h := &HostToolService{}
+	tarPath, err := h.ExportSupervisorProcessConfigs(request.SupervisorConfigsBackupReq{})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	defer os.RemoveAll(path.Dir(tarPath))
+
+	if err := os.RemoveAll(includeDir); err != nil {
+		t.Fatalf("failed to clear include dir: %v", err)
+	}
+	if err := os.MkdirAll(includeDir, 0755); err != nil {
+		t.Fatalf("failed to recreate include dir: %v", err)
+	}
+
+	if err := h.ImportSupervisorProcessConfigs(request.SupervisorConfigsRestoreReq{TarPath: tarPath}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	restored, err := ini.Load(path.Join(includeDir, "app1.ini"))
+	if err != nil {
+		t.Fatalf("expected app1.ini to be restored: %v", err)
+	}
+	restoredSection, err := restored.GetSection("program:app1")
+	if err != nil {
+		t.Fatalf("expected program:app1 section to be restored: %v", err)
+	}
+	if restoredSection.Key("command").Value() != "/bin/app1" {
+		t.Fatalf("unexpected restored command: %q", restoredSection.Key("command").Value())
+	}
+}
+
+func TestImportSupervisorProcessConfigsRejectsMalformedEntry(t *testing.T) {
+	withFakeSupervisorCtl(t, "true")
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origBaseDir := global.CONF.System.BaseDir
+	global.CONF.System.BaseDir = t.TempDir()
+	t.Cleanup(func() { global.CONF.System.BaseDir = origBaseDir })
+
+	tempDir := t.TempDir()
+	badIniDir := t.TempDir()
+	if err := os.WriteFile(path.Join(badIniDir, "bad.ini"), []byte("[supervisord]\nlogfile=/tmp/x.log\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture ini: %v", err)
+	}
+	if err := files.NewFileOp().Compress([]string{path.Join(badIniDir, "bad.ini")}, tempDir, "bad.tar.gz", files.TarGz); err != nil {
+		t.Fatalf("failed to build fixture tarball: %v", err)
+	}
+
+	h := &HostToolService{}
+	tarPath := path.Join(tempDir, "bad.tar.gz")
+	if err := h.ImportSupervisorProcessConfigs(request.SupervisorConfigsRestoreReq{TarPath: tarPath}); err == nil {
+		t.Fatal("expected an error for a tarball containing a malformed ini")
+	}
+}
+
+func TestOperateToolReturnsNotInstalledWhenSupervisordBinaryMissing(t *testing.T) {
+	withStubbedServiceAction(t, nil)
+
+	h := &HostToolService{}
+	_, err := h.OperateTool(request.HostToolReq{Type: constant.Supervisord, Operate: "restart"}, "127.0.0.1")
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrSupervisorNotInstalled" {
+		t.Fatalf("expected ErrSupervisorNotInstalled, got %#v", err)
+	}
+}
+
+func TestOperateToolReturnsServiceNotExistForUnregisteredService(t *testing.T) {
+	withStubbedServiceAction(t, nil)
+
+	h := &HostToolService{}
+	_, err := h.OperateTool(request.HostToolReq{Type: "a-service-that-does-not-exist", Operate: "restart"}, "127.0.0.1")
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrServiceNotExist" {
+		t.Fatalf("expected ErrServiceNotExist, got %#v", err)
+	}
+}
+
+func TestOperateToolRecordsFailedServiceActionLog(t *testing.T) {
+	recorded := withStubbedServiceAction(t, errors.New("boom"))
+
+	h := &HostToolService{}
+	req := request.HostToolReq{Type: "nginx", Operate: "stop", Manager: "systemd"}
+	if _, err := h.OperateTool(req, "10.0.0.1"); err == nil {
+		t.Fatal("expected the underlying operate error to be returned")
+	}
+
+	if len(*recorded) != 1 {
+		t.Fatalf("expected exactly one audit record, got %d", len(*recorded))
+	}
+	log := (*recorded)[0]
+	if log.Result != "failure" || log.OutputTail != "boom" {
+		t.Fatalf("unexpected audit record: %+v", log)
+	}
+}
+
+func TestValidateSupervisorInetConfigRejectsBadBindAddress(t *testing.T) {
+	err := validateSupervisorInetConfig(request.SupervisorInetConfigReq{BindAddress: "not-a-host-port"})
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrInvalidInetBindAddress" {
+		t.Fatalf("expected ErrInvalidInetBindAddress, got %v", err)
+	}
+}
+
+func TestValidateSupervisorInetConfigRejectsHalfSetCredentials(t *testing.T) {
+	err := validateSupervisorInetConfig(request.SupervisorInetConfigReq{BindAddress: "127.0.0.1:9001", Username: "admin"})
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrInetCredentialsIncomplete" {
+		t.Fatalf("expected ErrInetCredentialsIncomplete, got %v", err)
+	}
+}
+
+func TestValidateSupervisorInetConfigAcceptsNoCredentials(t *testing.T) {
+	if err := validateSupervisorInetConfig(request.SupervisorInetConfigReq{BindAddress: "127.0.0.1:9001"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestApplySupervisorInetConfigAddsSectionsToConfLackingThem(t *testing.T) {
+	cfg := ini.Empty()
+	req := request.SupervisorInetConfigReq{
+		BindAddress: "127.0.0.1:9001",
+		Username:    "admin",
+		Password:    "s3cret",
+	}
+
+	serverURL := applySupervisorInetConfig(cfg, req)
+	if serverURL != "http://127.0.0.1:9001" {
+		t.Fatalf("unexpected serverURL: %s", serverURL)
+	}
+
+	inetSection, err := cfg.GetSection("inet_http_server")
+	if err != nil {
+		t.Fatalf("expected [inet_http_server] section to be created: %v", err)
+	}
+	if inetSection.Key("port").Value() != "127.0.0.1:9001" {
+		t.Fatalf("unexpected inet port: %s", inetSection.Key("port").Value())
+	}
+	if inetSection.Key("username").Value() != "admin" {
+		t.Fatalf("unexpected inet username: %s", inetSection.Key("username").Value())
+	}
+	if inetSection.Key("password").Value() != hashSupervisorPassword("s3cret") {
+		t.Fatalf("expected inet password to be hashed, got %s", inetSection.Key("password").Value())
+	}
+
+	ctlSection, err := cfg.GetSection("supervisorctl")
+	if err != nil {
+		t.Fatalf("expected [supervisorctl] section to be created: %v", err)
+	}
+	if ctlSection.Key("serverurl").Value() != "http://127.0.0.1:9001" {
+		t.Fatalf("unexpected serverurl: %s", ctlSection.Key("serverurl").Value())
+	}
+	if ctlSection.Key("password").Value() != "s3cret" {
+		t.Fatalf("expected supervisorctl password to stay plaintext, got %s", ctlSection.Key("password").Value())
+	}
+}
+
+func TestApplySupervisorInetConfigWithoutCredentialsClearsExisting(t *testing.T) {
+	cfg := ini.Empty()
+	cfg.Section("inet_http_server").Key("username").SetValue("old")
+	cfg.Section("inet_http_server").Key("password").SetValue("old-hash")
+	cfg.Section("supervisorctl").Key("username").SetValue("old")
+	cfg.Section("supervisorctl").Key("password").SetValue("old-pass")
+
+	applySupervisorInetConfig(cfg, request.SupervisorInetConfigReq{BindAddress: "127.0.0.1:9001"})
+
+	inetSection, _ := cfg.GetSection("inet_http_server")
+	if inetSection.HasKey("username") || inetSection.HasKey("password") {
+		t.Fatal("expected inet_http_server credentials to be cleared")
+	}
+	ctlSection, _ := cfg.GetSection("supervisorctl")
+	if ctlSection.HasKey("username") || ctlSection.HasKey("password") {
+		t.Fatal("expected supervisorctl credentials to be cleared")
+	}
+}
+
+func TestValidateEventListenerBufferSizeAcceptsEmpty(t *testing.T) {
+	bufferSize, err := validateEventListenerBufferSize("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bufferSize != "" {
+		t.Fatalf("expected empty buffer size to stay empty, got %q", bufferSize)
+	}
+}
+
+func TestValidateEventListenerBufferSizeRejectsNonNumeric(t *testing.T) {
+	if _, err := validateEventListenerBufferSize("abc"); err == nil {
+		t.Fatal("expected \"abc\" to be rejected")
+	}
+}
+
+func TestValidateEventListenerBufferSizeRejectsZero(t *testing.T) {
+	if _, err := validateEventListenerBufferSize("0"); err == nil {
+		t.Fatal("expected \"0\" to be rejected")
+	}
+}
+
+func TestValidateEventListenerBufferSizeAcceptsPositiveInteger(t *testing.T) {
+	bufferSize, err := validateEventListenerBufferSize("50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bufferSize != "50" {
+		t.Fatalf("expected \"50\" to be preserved, got %q", bufferSize)
+	}
+}
+
+func TestSupervisorIncludeIsOursFindsOurGlobAmongOthers(t *testing.T) {
+	if !supervisorIncludeIsOurs("/etc/supervisor/conf.d/*.conf /data/1panel/tools/supervisord/supervisor.d/*.ini", "/data/1panel/tools/supervisord/supervisor.d/*.ini") {
+		t.Fatal("expected our glob to be found among multiple include globs")
+	}
+}
+
+func TestSupervisorIncludeIsOursRejectsUnrelatedGlob(t *testing.T) {
+	if supervisorIncludeIsOurs("/etc/supervisor/conf.d/*.conf", "/data/1panel/tools/supervisord/supervisor.d/*.ini") {
+		t.Fatal("expected an unrelated include glob not to be considered ours")
+	}
+}
+
+func TestRestoreSupervisorIncludeDropsOurGlobInCoexistMode(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ourGlob := "/data/1panel/tools/supervisord/supervisor.d/*.ini"
+	if _, err = section.NewKey("files", "/etc/supervisor/conf.d/*.conf "+ourGlob); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := restoreSupervisorInclude(cfg, ourGlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored {
+		t.Fatal("expected coexist include to be restored")
+	}
+	if section.Key("files").Value() != "/etc/supervisor/conf.d/*.conf" {
+		t.Fatalf("expected the other glob to remain active, got %q", section.Key("files").Value())
+	}
+}
+
+func TestRestoreSupervisorIncludeNoOpWhenOurGlobAbsent(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = section.NewKey("files", "/etc/supervisor/conf.d/*.conf"); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := restoreSupervisorInclude(cfg, "/data/1panel/tools/supervisord/supervisor.d/*.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatal("expected no restoration when our glob was never added")
+	}
+}
+
+func TestHashSupervisorPasswordUsesSHAPrefix(t *testing.T) {
+	hashed := hashSupervisorPassword("s3cret")
+	if !strings.HasPrefix(hashed, "{SHA}") {
+		t.Fatalf("expected {SHA} prefix, got %s", hashed)
+	}
+	if hashed != hashSupervisorPassword("s3cret") {
+		t.Fatal("expected hashing to be deterministic")
+	}
+	if hashed == hashSupervisorPassword("other") {
+		t.Fatal("expected different passwords to hash differently")
+	}
+}
+
+func TestStopSupervisorProcessSkipsForceKillWhenStopSucceeds(t *testing.T) {
+	killCalled := false
+	forced, err := stopSupervisorProcess("myapp",
+		func(string) error { return nil },
+		func(string) error { killCalled = true; return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forced || killCalled {
+		t.Fatal("expected no force-kill when the process stopped gracefully")
+	}
+}
+
+func TestStopSupervisorProcessForceKillsAProcessThatIgnoresStop(t *testing.T) {
+	killedName := ""
+	forced, err := stopSupervisorProcess("stubborn",
+		func(string) error { return buserr.New("ErrSupervisorCtlTimeout") },
+		func(name string) error { killedName = name; return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !forced {
+		t.Fatal("expected force-kill to have run")
+	}
+	if killedName != "stubborn" {
+		t.Fatalf("expected the kill to target the stuck process, got %q", killedName)
+	}
+}
+
+func TestStopSupervisorProcessPropagatesForceKillFailure(t *testing.T) {
+	_, err := stopSupervisorProcess("stubborn",
+		func(string) error { return buserr.New("ErrSupervisorCtlTimeout") },
+		func(string) error { return errors.New("no such process") },
+	)
+	if err == nil {
+		t.Fatal("expected an error when the force-kill itself fails")
+	}
+}
+
+func TestValidateSupervisorProcessConfigAcceptsValidContent(t *testing.T) {
+	content := "[program:myapp]\ncommand=/usr/bin/myapp\n"
+	result, err := validateSupervisorProcessConfig(content, "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || len(result.Errors) != 0 {
+		t.Fatalf("expected valid content to pass, got %+v", result)
+	}
+}
+
+func TestValidateSupervisorProcessConfigRejectsMissingCommand(t *testing.T) {
+	content := "[program:myapp]\ndirectory=/opt/myapp\n"
+	result, err := validateSupervisorProcessConfig(content, "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid {
+		t.Fatal("expected a missing command key to be rejected")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Section != "program:myapp" || result.Errors[0].Line != 1 {
+		t.Fatalf("unexpected errors: %+v", result.Errors)
+	}
+}
+
+func TestValidateSupervisorProcessConfigRejectsMismatchedSectionName(t *testing.T) {
+	content := "[program:otherapp]\ncommand=/usr/bin/otherapp\n"
+	result, err := validateSupervisorProcessConfig(content, "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid {
+		t.Fatal("expected a mismatched section name to be rejected")
+	}
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "program:myapp" && e.Message != "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an error naming the expected section, got %+v", result.Errors)
+	}
+}
+
+// fakeSettingRepoWithValues answers Get for the key most recently passed to
+// WithByKey with values[key] (reporting it as persisted), or a zero-value
+// Setting for any key absent from values.
+type fakeSettingRepoWithValues struct {
+	repo.ISettingRepo
+	values map[string]string
+	key    *string
+}
+
+func (f fakeSettingRepoWithValues) WithByKey(key string) repo.DBOption {
+	return func(db *gorm.DB) *gorm.DB {
+		*f.key = key
+		return db
+	}
+}
+
+func (f fakeSettingRepoWithValues) Get(opts ...repo.DBOption) (model.Setting, error) {
+	for _, opt := range opts {
+		opt(nil)
+	}
+	if v, ok := f.values[*f.key]; ok {
+		return model.Setting{BaseModel: model.BaseModel{ID: 1}, Key: *f.key, Value: v}, nil
+	}
+	return model.Setting{}, nil
+}
+
+func withFakeSettingValues(t *testing.T, values map[string]string) {
+	t.Helper()
+	origSettingRepo := settingRepo
+	var key string
+	settingRepo = fakeSettingRepoWithValues{values: values, key: &key}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+}
+
+func TestResolveSupervisorLogDirUsesConfiguredSetting(t *testing.T) {
+	withFakeSettingValues(t, map[string]string{constant.SupervisorLogDir: "/mnt/external/supervisor-logs"})
+	if got := resolveSupervisorLogDir(); got != "/mnt/external/supervisor-logs" {
+		t.Fatalf("expected the configured log dir to be honored, got %q", got)
+	}
+}
+
+func TestResolveSupervisorLogDirFallsBackToBaseDirWhenUnset(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	want := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "log")
+	if got := resolveSupervisorLogDir(); got != want {
+		t.Fatalf("resolveSupervisorLogDir() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveManagedSupervisorIncludeDirUsesConfiguredSetting(t *testing.T) {
+	withFakeSettingValues(t, map[string]string{constant.SupervisorIncludeDir: "/mnt/external/supervisor.d"})
+	if got := resolveManagedSupervisorIncludeDir(); got != "/mnt/external/supervisor.d" {
+		t.Fatalf("expected the configured include dir to be honored, got %q", got)
+	}
+}
+
+func TestResolveManagedSupervisorIncludeDirFallsBackToBaseDirWhenUnset(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	want := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d")
+	if got := resolveManagedSupervisorIncludeDir(); got != want {
+		t.Fatalf("resolveManagedSupervisorIncludeDir() = %q, want %q", got, want)
+	}
+}
+
+func TestEnsureDirWritableCreatesAndAcceptsAWritableDir(t *testing.T) {
+	dir := path.Join(t.TempDir(), "nested", "log")
+	fileOp := files.NewFileOp()
+	if err := ensureDirWritable(fileOp, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fileOp.Stat(dir) {
+		t.Fatal("expected ensureDirWritable to create the missing directory")
+	}
+}
+
+func TestSupervisorConfigDriftedFalseWhenIncludeMatchesManagedDir(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	dir := t.TempDir()
+	confPath := path.Join(dir, "supervisord.conf")
+	includePath := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d", "*.ini")
+	content := "[include]\nfiles = " + includePath + "\n"
+	if err := os.WriteFile(confPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if supervisorConfigDrifted(confPath) {
+		t.Fatal("expected no drift when the include glob matches the managed directory")
+	}
+}
+
+func TestSupervisorConfigDriftedTrueWhenIncludeSectionRemoved(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	dir := t.TempDir()
+	confPath := path.Join(dir, "supervisord.conf")
+	if err := os.WriteFile(confPath, []byte("[supervisord]\nlogfile = /tmp/x.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !supervisorConfigDrifted(confPath) {
+		t.Fatal("expected drift to be detected when the include section is missing")
+	}
+}
+
+func TestSupervisorConfigDriftedTrueWhenIncludePointsElsewhere(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	dir := t.TempDir()
+	confPath := path.Join(dir, "supervisord.conf")
+	content := "[include]\nfiles = /etc/supervisor/conf.d/*.conf\n"
+	if err := os.WriteFile(confPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !supervisorConfigDrifted(confPath) {
+		t.Fatal("expected drift to be detected when the include glob points elsewhere")
+	}
+}
+
+func TestRepairSupervisorIncludeSetsMissingFilesKey(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	cfg := ini.Empty()
+	if _, err := cfg.NewSection("include"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repairSupervisorInclude(cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d", "*.ini")
+	if got := cfg.Section("include").Key("files").Value(); got != want {
+		t.Fatalf("repairSupervisorInclude() files = %q, want %q", got, want)
+	}
+}
+
+func TestRepairSupervisorIncludePreservesOtherGlobs(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = section.NewKey("files", "/etc/supervisor/conf.d/*.conf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repairSupervisorInclude(cfg); err != nil {
+		t.Fatal(err)
+	}
+	got := section.Key("files").Value()
+	if !strings.Contains(got, "/etc/supervisor/conf.d/*.conf") {
+		t.Fatalf("expected the existing glob to be preserved, got %q", got)
+	}
+	if !strings.Contains(got, "supervisor.d/*.ini") {
+		t.Fatalf("expected our managed glob to be added, got %q", got)
+	}
+}
+
+func TestRepairSupervisorIncludeNoOpWhenAlreadyOurs(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	includePath := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d", "*.ini")
+	if _, err = section.NewKey("files", includePath); err != nil {
+		t.Fatal(err)
+	}
+	if err := repairSupervisorInclude(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := section.Key("files").Value(); got != includePath {
+		t.Fatalf("expected the value to be left unchanged, got %q", got)
+	}
+}
+
+func TestUnifiedConfigDiffReportsAddedLine(t *testing.T) {
+	diff, err := unifiedConfigDiff("a\nb\n", "a\nb\nc\n", "supervisord.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+c") {
+		t.Fatalf("expected the diff to report the added line, got %q", diff)
+	}
+	if strings.Contains(diff, "-a") || strings.Contains(diff, "-b") {
+		t.Fatalf("did not expect unchanged lines to show up as removed, got %q", diff)
+	}
+}
+
+func TestUnifiedConfigDiffReportsRemovedLine(t *testing.T) {
+	diff, err := unifiedConfigDiff("a\nb\nc\n", "a\nc\n", "supervisord.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "-b") {
+		t.Fatalf("expected the diff to report the removed line, got %q", diff)
+	}
+}
+
+func TestUnifiedConfigDiffEmptyWhenContentUnchanged(t *testing.T) {
+	diff, err := unifiedConfigDiff("a\nb\n", "a\nb\n", "supervisord.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Fatalf("expected no diff for identical content, got %q", diff)
+	}
+}
+
+func TestEnsureDirWritableRejectsAReadOnlyDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root bypasses directory permission bits")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+	fileOp := files.NewFileOp()
+	if err := ensureDirWritable(fileOp, dir); err == nil {
+		t.Fatal("expected a read-only directory to be rejected")
+	}
+}
+
+func currentOSUsername(t *testing.T) string {
+	t.Helper()
+	u, err := user.Current()
+	if err != nil {
+		t.Skipf("could not resolve current OS user: %v", err)
+	}
+	return u.Username
+}
+
+func TestOperateSupervisorProcessCreateWithDirectory(t *testing.T) {
+	withFakeSupervisorCtl(t, "true")
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origBaseDir := global.CONF.System.BaseDir
+	global.CONF.System.BaseDir = t.TempDir()
+	t.Cleanup(func() { global.CONF.System.BaseDir = origBaseDir })
+
+	includeDir := resolveSupervisorIncludeDir()
+	if err := os.MkdirAll(includeDir, 0755); err != nil {
+		t.Fatalf("failed to create include dir: %v", err)
+	}
+
+	dir := t.TempDir()
+	h := &HostToolService{}
+	req := request.SupervisorProcessConfig{
+		Name:    "app1",
+		Operate: "create",
+		Command: "/bin/app1",
+		User:    currentOSUsername(t),
+		Dir:     dir,
+	}
+	if err := h.OperateSupervisorProcess(req); err != nil {
+		t.Fatalf("expected create to succeed, got %v", err)
+	}
+
+	cfg, err := ini.Load(path.Join(resolveSupervisorIncludeDir(), "app1.ini"))
+	if err != nil {
+		t.Fatalf("failed to load created ini: %v", err)
+	}
+	section, err := cfg.GetSection("program:app1")
+	if err != nil {
+		t.Fatalf("expected program:app1 section, got err: %v", err)
+	}
+	if directory, _ := section.GetKey("directory"); directory == nil || directory.Value() != dir {
+		t.Fatalf("expected directory key to be set to %q", dir)
+	}
+}
+
+func TestOperateSupervisorProcessCreateWithoutDirectory(t *testing.T) {
+	withFakeSupervisorCtl(t, "true")
+
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origBaseDir := global.CONF.System.BaseDir
+	global.CONF.System.BaseDir = t.TempDir()
+	t.Cleanup(func() { global.CONF.System.BaseDir = origBaseDir })
+
+	includeDir := resolveSupervisorIncludeDir()
+	if err := os.MkdirAll(includeDir, 0755); err != nil {
+		t.Fatalf("failed to create include dir: %v", err)
+	}
+
+	h := &HostToolService{}
+	req := request.SupervisorProcessConfig{
+		Name:    "app1",
+		Operate: "create",
+		Command: "/bin/app1",
+		User:    currentOSUsername(t),
+	}
+	if err := h.OperateSupervisorProcess(req); err != nil {
+		t.Fatalf("expected create without a directory to succeed, got %v", err)
+	}
+
+	cfg, err := ini.Load(path.Join(resolveSupervisorIncludeDir(), "app1.ini"))
+	if err != nil {
+		t.Fatalf("failed to load created ini: %v", err)
+	}
+	section, err := cfg.GetSection("program:app1")
+	if err != nil {
+		t.Fatalf("expected program:app1 section, got err: %v", err)
+	}
+	if directory, _ := section.GetKey("directory"); directory != nil && directory.Value() != "" {
+		t.Fatalf("expected no directory key to be set, got %q", directory.Value())
+	}
+}
+
+func TestOperateSupervisorProcessCreateRejectsEmptyCommand(t *testing.T) {
+	origSettingRepo := settingRepo
+	settingRepo = fakeSettingRepoWithNoCustomCommands{}
+	t.Cleanup(func() { settingRepo = origSettingRepo })
+
+	origBaseDir := global.CONF.System.BaseDir
+	global.CONF.System.BaseDir = t.TempDir()
+	t.Cleanup(func() { global.CONF.System.BaseDir = origBaseDir })
+
+	h := &HostToolService{}
+	req := request.SupervisorProcessConfig{
+		Name:    "app1",
+		Operate: "create",
+		Command: "   ",
+		User:    currentOSUsername(t),
+	}
+	err := h.OperateSupervisorProcess(req)
+	if err == nil {
+		t.Fatal("expected an error when command is empty")
+	}
+	businessErr, ok := err.(buserr.BusinessError)
+	if !ok || businessErr.Msg != "ErrConfigCommandRequired" {
+		t.Fatalf("expected ErrConfigCommandRequired, got %#v", err)
+	}
+}
+
+func TestTestSupervisorProcessCommandCapturesQuickSuccess(t *testing.T) {
+	h := &HostToolService{}
+	req := request.SupervisorProcessTestReq{
+		Command: "echo hello",
+		User:    currentOSUsername(t),
+	}
+	result, err := h.TestSupervisorProcessCommand(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TimedOut {
+		t.Fatal("did not expect a quick-succeeding command to time out")
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+	if strings.TrimSpace(result.Stdout) != "hello" {
+		t.Fatalf("expected stdout %q, got %q", "hello", result.Stdout)
+	}
+}
+
+func TestTestSupervisorProcessCommandCapturesImmediateFailure(t *testing.T) {
+	h := &HostToolService{}
+	req := request.SupervisorProcessTestReq{
+		Command: "echo oops 1>&2; exit 7",
+		User:    currentOSUsername(t),
+	}
+	result, err := h.TestSupervisorProcessCommand(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TimedOut {
+		t.Fatal("did not expect an immediately-failing command to time out")
+	}
+	if result.ExitCode != 7 {
+		t.Fatalf("expected exit code 7, got %d", result.ExitCode)
+	}
+	if strings.TrimSpace(result.Stderr) != "oops" {
+		t.Fatalf("expected stderr %q, got %q", "oops", result.Stderr)
+	}
+}
+
+func TestTestSupervisorProcessCommandRejectsEmptyCommand(t *testing.T) {
+	h := &HostToolService{}
+	req := request.SupervisorProcessTestReq{User: currentOSUsername(t)}
+	_, err := h.TestSupervisorProcessCommand(req)
+	if err == nil {
+		t.Fatal("expected an error when command is empty")
+	}
+}
+
+func TestTestSupervisorProcessCommandRejectsMissingDir(t *testing.T) {
+	h := &HostToolService{}
+	req := request.SupervisorProcessTestReq{
+		Command: "echo hello",
+		User:    currentOSUsername(t),
+		Dir:     path.Join(t.TempDir(), "does-not-exist"),
+	}
+	_, err := h.TestSupervisorProcessCommand(req)
+	if err == nil {
+		t.Fatal("expected an error when dir does not exist")
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputRunning(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("app1                             RUNNING   pid 1234, uptime 1:02:03\n")
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0] != (response.ProcessStatus{Name: "app1", Status: "RUNNING", PID: "1234", Uptime: "1:02:03"}) {
+		t.Fatalf("unexpected status: %+v", statuses[0])
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputStarting(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("app1                             STARTING\n")
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0].Name != "app1" || statuses[0].Status != "STARTING" {
+		t.Fatalf("unexpected status: %+v", statuses[0])
+	}
+	if statuses[0].PID != "" || statuses[0].Uptime != "" {
+		t.Fatalf("expected no pid/uptime for a non-running status, got %+v", statuses[0])
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputBackoffWithMessage(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("app1                             BACKOFF   Exited too quickly (process log may have requested restart)\n")
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0].Status != "BACKOFF" || statuses[0].Msg != "Exited too quickly (process log may have requested restart)" {
+		t.Fatalf("unexpected status: %+v", statuses[0])
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputGroupedProcessName(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("mygroup:mygroup_00               RUNNING   pid 5678, uptime 0:00:05\n")
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0].Name != "mygroup:mygroup_00" || statuses[0].PID != "5678" || statuses[0].Uptime != "0:00:05" {
+		t.Fatalf("unexpected status: %+v", statuses[0])
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputSkipsBlankLines(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("app1 RUNNING pid 1, uptime 0:00:01\n\napp2 RUNNING pid 2, uptime 0:00:02\n")
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d: %+v", len(statuses), statuses)
+	}
+}
+
+func TestParseSupervisorCtlStatusOutputRunningWithoutPIDFragmentHasNoPanic(t *testing.T) {
+	statuses := parseSupervisorCtlStatusOutput("app1 RUNNING\n")
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(statuses))
+	}
+	if statuses[0].PID != "" || statuses[0].Uptime != "" {
+		t.Fatalf("expected no pid/uptime when the pattern doesn't match, got %+v", statuses[0])
+	}
+}
+
+func TestParseProcStatTimesParsesFieldsAfterCommWithSpaces(t *testing.T) {
+	line := "1234 (my proc) S 1 1234 1234 0 -1 4194304 100 0 0 0 500 200 0 0 20 0 1 0 1000\n"
+	utime, stime, starttime, err := parseProcStatTimes(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if utime != 500 || stime != 200 || starttime != 1000 {
+		t.Fatalf("got utime=%d stime=%d starttime=%d, want 500, 200, 1000", utime, stime, starttime)
+	}
+}
+
+func TestParseProcStatTimesRejectsMalformedLine(t *testing.T) {
+	if _, _, _, err := parseProcStatTimes("not a stat line"); err == nil {
+		t.Fatal("expected an error for a line with no comm parentheses")
+	}
+}
+
+func TestParseProcStatTimesRejectsTruncatedLine(t *testing.T) {
+	if _, _, _, err := parseProcStatTimes("1234 (proc) S 1 1234"); err == nil {
+		t.Fatal("expected an error when fewer fields than starttime are present")
+	}
+}
+
+func TestParseProcStatusVMRSSFindsLine(t *testing.T) {
+	content := "VmPeak:\t    4096 kB\nVmRSS:\t    2048 kB\nVmSize:\t    4096 kB\n"
+	rss, err := parseProcStatusVMRSS(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rss != 2048 {
+		t.Fatalf("got %d, want 2048", rss)
+	}
+}
+
+func TestParseProcStatusVMRSSMissingLineErrors(t *testing.T) {
+	if _, err := parseProcStatusVMRSS("VmPeak:\t 4096 kB\n"); err == nil {
+		t.Fatal("expected an error when VmRSS is absent")
+	}
+}
+
+func TestProcessCPUPercentComputesLifetimeAverage(t *testing.T) {
+	summary := procStatSummary{utimeTicks: 500, stimeTicks: 200, starttimeTicks: 1000}
+	got := processCPUPercent(summary, 5010.0)
+	want := 0.14
+	if got < want-0.0001 || got > want+0.0001 {
+		t.Fatalf("got %v, want ~%v", got, want)
+	}
+}
+
+func TestProcessCPUPercentZeroWhenAgeNonPositive(t *testing.T) {
+	summary := procStatSummary{utimeTicks: 500, stimeTicks: 200, starttimeTicks: 1000}
+	if got := processCPUPercent(summary, 5.0); got != 0 {
+		t.Fatalf("expected 0 when the process looks newer than uptime allows, got %v", got)
+	}
+}
+
+// withFakeProcRoot points procRoot at a fixture directory containing
+// /proc/uptime and a /proc/<pid>/{stat,status} pair, the same layout
+// readProcStatSummary/readSystemUptimeSeconds read from the real procfs.
+func withFakeProcRoot(t *testing.T, pid int, statLine, statusContent, uptimeContent string) {
+	t.Helper()
+	dir := t.TempDir()
+	pidDir := path.Join(dir, strconv.Itoa(pid))
+	if err := os.MkdirAll(pidDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(pidDir, "stat"), []byte(statLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(pidDir, "status"), []byte(statusContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, "uptime"), []byte(uptimeContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origProcRoot := procRoot
+	procRoot = dir
+	t.Cleanup(func() { procRoot = origProcRoot })
+}
+
+func TestEnrichProcessResourceUsagePopulatesRunningEntry(t *testing.T) {
+	withFakeProcRoot(t, 1234,
+		"1234 (my proc) S 1 1234 1234 0 -1 4194304 100 0 0 0 500 200 0 0 20 0 1 0 1000\n",
+		"VmRSS:\t    2048 kB\n",
+		"5010.0 1234.0\n",
+	)
+	statuses := []response.ProcessStatus{{Name: "app", Status: "RUNNING", PID: "1234"}}
+	enrichProcessResourceUsage(statuses)
+	if statuses[0].RSSBytes != 2048*1024 {
+		t.Fatalf("got RSSBytes=%d, want %d", statuses[0].RSSBytes, 2048*1024)
+	}
+	if statuses[0].CPUPercent <= 0 {
+		t.Fatalf("expected a positive CPUPercent, got %v", statuses[0].CPUPercent)
+	}
+}
+
+func TestEnrichProcessResourceUsageSkipsNonRunningAndMissingPID(t *testing.T) {
+	withFakeProcRoot(t, 1234,
+		"1234 (my proc) S 1 1234 1234 0 -1 4194304 100 0 0 0 500 200 0 0 20 0 1 0 1000\n",
+		"VmRSS:\t    2048 kB\n",
+		"5010.0 1234.0\n",
+	)
+	statuses := []response.ProcessStatus{
+		{Name: "stopped", Status: "STOPPED"},
+		{Name: "unknown-pid", Status: "RUNNING"},
+	}
+	enrichProcessResourceUsage(statuses)
+	for _, s := range statuses {
+		if s.RSSBytes != 0 || s.CPUPercent != 0 {
+			t.Fatalf("expected no enrichment for %q, got %+v", s.Name, s)
+		}
+	}
+}
+
+func TestEnrichProcessResourceUsageNoopWhenProcUnavailable(t *testing.T) {
+	origProcRoot := procRoot
+	procRoot = path.Join(t.TempDir(), "does-not-exist")
+	t.Cleanup(func() { procRoot = origProcRoot })
+
+	statuses := []response.ProcessStatus{{Name: "app", Status: "RUNNING", PID: "1234"}}
+	enrichProcessResourceUsage(statuses)
+	if statuses[0].RSSBytes != 0 || statuses[0].CPUPercent != 0 {
+		t.Fatalf("expected no enrichment when /proc is unavailable, got %+v", statuses[0])
+	}
+}
+
+func TestResolveSupervisorConfigPathFromInitScriptFindsCFlag(t *testing.T) {
+	script := "#!/sbin/openrc-run\ncommand=\"/usr/bin/supervisord\"\ncommand_args=\"-c /etc/supervisor/supervisord.conf\"\n"
+	scriptPath := path.Join(t.TempDir(), "supervisord")
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	got := resolveSupervisorConfigPathFromInitScript(scriptPath)
+	if got != "/etc/supervisor/supervisord.conf" {
+		t.Fatalf("got %q, want /etc/supervisor/supervisord.conf", got)
+	}
+}
+
+func TestResolveSupervisorConfigPathFromInitScriptMissingFlag(t *testing.T) {
+	script := "#!/sbin/openrc-run\ncommand=\"/usr/bin/supervisord\"\n"
+	scriptPath := path.Join(t.TempDir(), "supervisord")
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveSupervisorConfigPathFromInitScript(scriptPath); got != "" {
+		t.Fatalf("expected empty result, got %q", got)
+	}
+}
+
+func TestResolveSupervisorConfigPathFromInitScriptMissingFile(t *testing.T) {
+	if got := resolveSupervisorConfigPathFromInitScript(path.Join(t.TempDir(), "does-not-exist")); got != "" {
+		t.Fatalf("expected empty result, got %q", got)
+	}
+}
+
+func TestResolveSupervisordBinaryUsesConfiguredSetting(t *testing.T) {
+	withFakeSettingValues(t, map[string]string{constant.SupervisordBinary: "/opt/venv/bin/supervisord"})
+	if got := resolveSupervisordBinary(); got != "/opt/venv/bin/supervisord" {
+		t.Fatalf("expected the configured binary to be honored, got %q", got)
+	}
+}
+
+func TestResolveSupervisordBinaryFallsBackToBareNameWhenUnset(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	if got := resolveSupervisordBinary(); got != constant.Supervisord {
+		t.Fatalf("resolveSupervisordBinary() = %q, want %q", got, constant.Supervisord)
+	}
+}
+
+func TestResolveSupervisorctlBinaryUsesConfiguredSetting(t *testing.T) {
+	withFakeSettingValues(t, map[string]string{constant.SupervisorctlBinary: "/opt/venv/bin/supervisorctl"})
+	if got := resolveSupervisorctlBinary(); got != "/opt/venv/bin/supervisorctl" {
+		t.Fatalf("expected the configured binary to be honored, got %q", got)
+	}
+}
+
+func TestResolveSupervisorctlBinaryFallsBackToBareNameWhenUnset(t *testing.T) {
+	withFakeSettingValues(t, nil)
+	if got := resolveSupervisorctlBinary(); got != "supervisorctl" {
+		t.Fatalf("resolveSupervisorctlBinary() = %q, want %q", got, "supervisorctl")
+	}
+}
+
+// TestOperateSupervisorCtlUsesConfiguredCustomBinaryPath asserts that once a
+// custom SupervisorctlBinary setting is configured, operateSupervisorCtl
+// actually invokes that path (not the bare "supervisorctl" name) to build
+// its command.
+func TestOperateSupervisorCtlUsesConfiguredCustomBinaryPath(t *testing.T) {
+	dir := t.TempDir()
+	customPath := dir + "/my-custom-supervisorctl"
+	markerPath := dir + "/invoked-with"
+	script := "#!/bin/sh\necho \"$0\" > " + markerPath + "\n"
+	if err := os.WriteFile(customPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	withFakeSettingValues(t, map[string]string{constant.SupervisorctlBinary: customPath})
+
+	origLookup := supervisorCtlLookup
+	supervisorCtlLookup = func(string) (string, error) { return customPath, nil }
+	t.Cleanup(func() { supervisorCtlLookup = origLookup })
+
+	origTimeout := defaultSupervisorCtlTimeout
+	defaultSupervisorCtlTimeout = 2 * time.Second
+	t.Cleanup(func() { defaultSupervisorCtlTimeout = origTimeout })
+
+	if err := operateSupervisorCtl("status", "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invoked, err := os.ReadFile(markerPath)
+	if err != nil {
+		t.Fatalf("expected the configured custom binary to have run, got error reading marker: %v", err)
+	}
+	if strings.TrimSpace(string(invoked)) != customPath {
+		t.Fatalf("expected the built command to invoke %q, got %q", customPath, strings.TrimSpace(string(invoked)))
+	}
+}
+
+func TestParseSupervisorRereadOutputSortsIntoAddedChangedRemoved(t *testing.T) {
+	added, changed, removed := parseSupervisorRereadOutput("foo: available\nbar: changed\nbaz: disappeared\n")
+	if len(added) != 1 || added[0] != "foo" {
+		t.Fatalf("unexpected added: %v", added)
+	}
+	if len(changed) != 1 || changed[0] != "bar" {
+		t.Fatalf("unexpected changed: %v", changed)
+	}
+	if len(removed) != 1 || removed[0] != "baz" {
+		t.Fatalf("unexpected removed: %v", removed)
+	}
+}
+
+func TestParseSupervisorRereadOutputEmptyWhenNothingChanged(t *testing.T) {
+	added, changed, removed := parseSupervisorRereadOutput("")
+	if len(added) != 0 || len(changed) != 0 || len(removed) != 0 {
+		t.Fatalf("expected no entries, got added=%v changed=%v removed=%v", added, changed, removed)
+	}
+}
+
+func TestParseSupervisorRereadOutputIgnoresUnrecognizedLines(t *testing.T) {
+	added, changed, removed := parseSupervisorRereadOutput("foo: available\nsome unrelated line\n\n")
+	if len(added) != 1 || added[0] != "foo" {
+		t.Fatalf("unexpected added: %v", added)
+	}
+	if len(changed) != 0 || len(removed) != 0 {
+		t.Fatalf("expected unrecognized lines to be ignored, got changed=%v removed=%v", changed, removed)
+	}
+}
+
+func TestOperateSupervisorReloadRereadReturnsParsedResult(t *testing.T) {
+	withFakeSupervisorCtl(t, "printf 'foo: available\\nbar: changed\\nbaz: disappeared\\n'")
+
+	h := &HostToolService{}
+	result, err := h.OperateSupervisorReload(request.SupervisorReloadReq{Operate: "reread"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "foo" {
+		t.Fatalf("unexpected added: %v", result.Added)
+	}
+	if len(result.Changed) != 1 || result.Changed[0] != "bar" {
+		t.Fatalf("unexpected changed: %v", result.Changed)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != "baz" {
+		t.Fatalf("unexpected removed: %v", result.Removed)
+	}
+}
+
+func TestOperateSupervisorReloadReloadSucceeds(t *testing.T) {
+	withFakeSupervisorCtl(t, "true")
+
+	h := &HostToolService{}
+	result, err := h.OperateSupervisorReload(request.SupervisorReloadReq{Operate: "reload"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Added) != 0 || len(result.Changed) != 0 || len(result.Removed) != 0 {
+		t.Fatalf("expected an empty result after reload, got %+v", result)
+	}
+}
+
+func TestOperateSupervisorReloadRereadPropagatesMissingCtlError(t *testing.T) {
+	withMissingSupervisorCtl(t)
+
+	h := &HostToolService{}
+	if _, err := h.OperateSupervisorReload(request.SupervisorReloadReq{Operate: "reread"}); err == nil {
+		t.Fatal("expected an error when supervisorctl is missing")
+	}
+}
+
+func TestValidateSupervisorBinaryPathAcceptsEmpty(t *testing.T) {
+	if err := validateSupervisorBinaryPath(""); err != nil {
+		t.Fatalf("expected an empty path to be accepted as a PATH-lookup fallback, got %v", err)
+	}
+}
+
+func TestValidateSupervisorBinaryPathAcceptsExecutableFile(t *testing.T) {
+	dir := t.TempDir()
+	binPath := dir + "/fake-supervisord"
+	if err := os.WriteFile(binPath, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSupervisorBinaryPath(binPath); err != nil {
+		t.Fatalf("expected an executable path to be accepted, got %v", err)
+	}
+}
+
+func TestValidateSupervisorBinaryPathRejectsMissingFile(t *testing.T) {
+	if err := validateSupervisorBinaryPath("/no/such/binary/here"); err == nil {
+		t.Fatal("expected an error for a binary path that doesn't resolve")
+	}
+}
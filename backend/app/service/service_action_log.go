@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/1Panel-dev/1Panel/backend/app/model"
+	"github.com/1Panel-dev/1Panel/backend/global"
+)
+
+// serviceActionLogRetention bounds how many service action audit records
+// are kept; older rows are pruned after every write so the table doesn't
+// grow without bound.
+const serviceActionLogRetention = 500
+
+// maxServiceActionOutputTail caps how much of a command's output is stored
+// alongside an audit record, enough to diagnose a failure without the table
+// ballooning from a noisy service.
+const maxServiceActionOutputTail = 1024
+
+// createServiceActionLog persists a single audit record; overridden in
+// tests so callers that trigger service actions can be exercised without a
+// live database.
+var createServiceActionLog = func(log *model.ServiceActionLog) error {
+	return logRepo.CreateServiceActionLog(log)
+}
+
+// pruneServiceActionLogs trims the table down to serviceActionLogRetention
+// rows; overridden in tests alongside createServiceActionLog.
+var pruneServiceActionLogs = func(keep int) error {
+	return logRepo.PruneServiceActionLogs(keep)
+}
+
+// recordServiceAction audits a start/stop/restart/enable/disable/upgrade/
+// rollback action taken against a service, independent of the generic
+// per-request OperationLog. Persistence failures are logged, not returned,
+// so a broken audit trail never blocks the action it's recording.
+func recordServiceAction(operator, serviceName, action, result string, output string) {
+	if len(output) > maxServiceActionOutputTail {
+		output = output[len(output)-maxServiceActionOutputTail:]
+	}
+	log := &model.ServiceActionLog{
+		Operator:    operator,
+		ServiceName: serviceName,
+		Action:      action,
+		Result:      result,
+		OutputTail:  output,
+	}
+	if err := createServiceActionLog(log); err != nil {
+		global.LOG.Errorf("record service action log failed, err: %v", err)
+		return
+	}
+	if err := pruneServiceActionLogs(serviceActionLogRetention); err != nil {
+		global.LOG.Warnf("prune service action logs failed, err: %v", err)
+	}
+}
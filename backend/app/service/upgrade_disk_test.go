@@ -0,0 +1,19 @@
+package service
+
+import "testing"
+
+func TestCheckDiskSpaceAllowsSmallRequirement(t *testing.T) {
+	if err := checkDiskSpace(t.TempDir(), 1); err != nil {
+		t.Fatalf("expected a 1-byte requirement to pass, got %v", err)
+	}
+}
+
+func TestCheckDiskSpaceRejectsHugeRequirement(t *testing.T) {
+	err := checkDiskSpace(t.TempDir(), 1<<60)
+	if _, ok, statErr := availableDiskSpace(t.TempDir()); !ok || statErr != nil {
+		t.Skip("disk space check not supported on this platform")
+	}
+	if err == nil {
+		t.Fatal("expected an error when required space vastly exceeds what's available")
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTryAcquireUpgradeLockOnlyOneWinnerUnderConcurrency(t *testing.T) {
+	upgradeInProgress.Store(false)
+	defer upgradeInProgress.Store(false)
+
+	const attempts = 50
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	wg.Add(attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+			if tryAcquireUpgradeLock() {
+				wins.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wins.Load() != 1 {
+		t.Fatalf("expected exactly one concurrent caller to acquire the lock, got %d", wins.Load())
+	}
+}
+
+func TestReleaseUpgradeLockAllowsReacquire(t *testing.T) {
+	upgradeInProgress.Store(false)
+	defer upgradeInProgress.Store(false)
+
+	if !tryAcquireUpgradeLock() {
+		t.Fatal("expected the first acquire to succeed")
+	}
+	if tryAcquireUpgradeLock() {
+		t.Fatal("expected a second acquire to fail while the lock is held")
+	}
+	releaseUpgradeLock()
+	if !tryAcquireUpgradeLock() {
+		t.Fatal("expected reacquire to succeed after release")
+	}
+}
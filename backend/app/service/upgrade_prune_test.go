@@ -0,0 +1,68 @@
+package service
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func makeSnapshot(t *testing.T, root, name string, complete bool) {
+	t.Helper()
+	dir := path.Join(root, name)
+	if err := os.MkdirAll(path.Join(dir, "downloads"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if !complete {
+		return
+	}
+	originalDir := path.Join(dir, "original")
+	if err := os.MkdirAll(originalDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(originalDir, "1panel"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneUpgradeSnapshotsKeepsNewestAndRemovesIncomplete(t *testing.T) {
+	root := t.TempDir()
+	makeSnapshot(t, root, "upgrade_20240101000000", true)
+	makeSnapshot(t, root, "upgrade_20240102000000", true)
+	makeSnapshot(t, root, "upgrade_20240103000000", true)
+	makeSnapshot(t, root, "upgrade_20240104000000", false)
+
+	if err := pruneUpgradeSnapshots(root, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, entry := range remaining {
+		names[entry.Name()] = true
+	}
+	if names["upgrade_20240104000000"] {
+		t.Fatal("incomplete snapshot should have been removed")
+	}
+	if names["upgrade_20240101000000"] {
+		t.Fatal("oldest complete snapshot beyond retention should have been removed")
+	}
+	if !names["upgrade_20240102000000"] || !names["upgrade_20240103000000"] {
+		t.Fatalf("expected the 2 most recent complete snapshots to survive, got %v", names)
+	}
+}
+
+func TestPruneUpgradeSnapshotsNeverDeletesTheOnlySnapshot(t *testing.T) {
+	root := t.TempDir()
+	makeSnapshot(t, root, "upgrade_20240101000000", true)
+
+	if err := pruneUpgradeSnapshots(root, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path.Join(root, "upgrade_20240101000000", "original")); err != nil {
+		t.Fatal("the only complete snapshot should never be pruned, even with keep=0")
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/1Panel-dev/1Panel/backend/app/dto"
+)
+
+// Upgrade phases, in the order Upgrade's goroutine moves through them.
+const (
+	UpgradePhaseIdle        = "Idle"
+	UpgradePhaseDownloading = "Downloading"
+	UpgradePhaseExtracting  = "Extracting"
+	UpgradePhaseBackingUp   = "BackingUp"
+	UpgradePhaseInstalling  = "Installing"
+	UpgradePhaseMigrating   = "Migrating"
+	UpgradePhaseRestarting  = "Restarting"
+	UpgradePhaseSuccess     = "Success"
+	UpgradePhaseFailed      = "Failed"
+	UpgradePhaseRollingBack = "RollingBack"
+	UpgradePhaseRolledBack  = "RolledBack"
+)
+
+// upgradePhasePercent is the coarse percent-complete reported for each
+// phase: a fixed checkpoint rather than a fine-grained progress bar, since
+// most phases (backup, binary swap, migrations) have no meaningful
+// sub-progress of their own to report.
+var upgradePhasePercent = map[string]int{
+	UpgradePhaseIdle:        0,
+	UpgradePhaseDownloading: 10,
+	UpgradePhaseExtracting:  30,
+	UpgradePhaseBackingUp:   45,
+	UpgradePhaseInstalling:  65,
+	UpgradePhaseMigrating:   85,
+	UpgradePhaseRestarting:  95,
+	UpgradePhaseSuccess:     100,
+	UpgradePhaseFailed:      100,
+	UpgradePhaseRollingBack: 50,
+	UpgradePhaseRolledBack:  100,
+}
+
+// upgradeStateMachine is the in-memory record of an upgrade in progress,
+// guarded by mu so UpgradeStatus can be read from a different goroutine
+// than the one driving Upgrade without racing its writes. settingRepo's
+// SystemStatus key remains the source of truth persisted at phase
+// boundaries (Free, Upgrading, UpgradeNeedsAttention, RollbackFailed); this
+// tracks the finer detail in between that isn't worth a DB write for.
+type upgradeStateMachine struct {
+	mu             sync.Mutex
+	phase          string
+	percent        int
+	currentVersion string
+	targetVersion  string
+	lastError      string
+}
+
+var upgradeState = upgradeStateMachine{phase: UpgradePhaseIdle}
+
+// setUpgradePhase moves the in-memory upgrade state machine to phase,
+// recording currentVersion/targetVersion and clearing any previous
+// lastError.
+func setUpgradePhase(phase, currentVersion, targetVersion string) {
+	upgradeState.mu.Lock()
+	defer upgradeState.mu.Unlock()
+	upgradeState.phase = phase
+	upgradeState.percent = upgradePhasePercent[phase]
+	upgradeState.currentVersion = currentVersion
+	upgradeState.targetVersion = targetVersion
+	upgradeState.lastError = ""
+}
+
+// failUpgradePhase moves the state machine to UpgradePhaseFailed, recording
+// err as lastError for UpgradeStatus to surface. A nil err leaves lastError
+// untouched (the failing step already logged its own error).
+func failUpgradePhase(err error) {
+	upgradeState.mu.Lock()
+	defer upgradeState.mu.Unlock()
+	upgradeState.phase = UpgradePhaseFailed
+	upgradeState.percent = upgradePhasePercent[UpgradePhaseFailed]
+	if err != nil {
+		upgradeState.lastError = err.Error()
+	}
+}
+
+// UpgradeStatus reports the in-memory upgrade state machine's current
+// phase, so the UI can poll progress during an upgrade without hammering
+// the DB the way repeatedly reading the SystemStatus setting would.
+func (u *UpgradeService) UpgradeStatus() dto.UpgradeStatus {
+	upgradeState.mu.Lock()
+	defer upgradeState.mu.Unlock()
+	return dto.UpgradeStatus{
+		Phase:          upgradeState.phase,
+		Percent:        upgradeState.percent,
+		CurrentVersion: upgradeState.currentVersion,
+		TargetVersion:  upgradeState.targetVersion,
+		LastError:      upgradeState.lastError,
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+// resetUpgradeState restores the package-level upgrade state machine to its
+// zero (Idle) state after a test has driven it through transitions, so
+// tests don't leak state into each other.
+func resetUpgradeState(t *testing.T) {
+	t.Helper()
+	upgradeState.mu.Lock()
+	upgradeState.phase = UpgradePhaseIdle
+	upgradeState.percent = 0
+	upgradeState.currentVersion = ""
+	upgradeState.targetVersion = ""
+	upgradeState.lastError = ""
+	upgradeState.mu.Unlock()
+	t.Cleanup(func() {
+		upgradeState.mu.Lock()
+		upgradeState.phase = UpgradePhaseIdle
+		upgradeState.percent = 0
+		upgradeState.currentVersion = ""
+		upgradeState.targetVersion = ""
+		upgradeState.lastError = ""
+		upgradeState.mu.Unlock()
+	})
+}
+
+func TestUpgradeStateMachineDrivesSuccessTransitions(t *testing.T) {
+	resetUpgradeState(t)
+	u := &UpgradeService{}
+
+	setUpgradePhase(UpgradePhaseDownloading, "v1.0.0", "v1.1.0")
+	status := u.UpgradeStatus()
+	if status.Phase != UpgradePhaseDownloading || status.CurrentVersion != "v1.0.0" || status.TargetVersion != "v1.1.0" {
+		t.Fatalf("unexpected status after Downloading: %+v", status)
+	}
+
+	for _, phase := range []string{UpgradePhaseExtracting, UpgradePhaseBackingUp, UpgradePhaseInstalling, UpgradePhaseMigrating, UpgradePhaseRestarting, UpgradePhaseSuccess} {
+		setUpgradePhase(phase, "v1.0.0", "v1.1.0")
+		status = u.UpgradeStatus()
+		if status.Phase != phase {
+			t.Fatalf("expected phase %q, got %q", phase, status.Phase)
+		}
+		if status.Percent != upgradePhasePercent[phase] {
+			t.Fatalf("expected percent %d for phase %q, got %d", upgradePhasePercent[phase], phase, status.Percent)
+		}
+		if status.LastError != "" {
+			t.Fatalf("expected no lastError mid-run, got %q", status.LastError)
+		}
+	}
+}
+
+func TestUpgradeStateMachineDrivesFailureTransition(t *testing.T) {
+	resetUpgradeState(t)
+	u := &UpgradeService{}
+
+	setUpgradePhase(UpgradePhaseInstalling, "v1.0.0", "v1.1.0")
+	failUpgradePhase(errors.New("disk full"))
+
+	status := u.UpgradeStatus()
+	if status.Phase != UpgradePhaseFailed {
+		t.Fatalf("expected Failed phase, got %q", status.Phase)
+	}
+	if status.LastError != "disk full" {
+		t.Fatalf("expected lastError %q, got %q", "disk full", status.LastError)
+	}
+	if status.Percent != upgradePhasePercent[UpgradePhaseFailed] {
+		t.Fatalf("expected percent %d, got %d", upgradePhasePercent[UpgradePhaseFailed], status.Percent)
+	}
+}
+
+func TestUpgradeStateMachineNextRunClearsPriorError(t *testing.T) {
+	resetUpgradeState(t)
+	u := &UpgradeService{}
+
+	setUpgradePhase(UpgradePhaseDownloading, "v1.0.0", "v1.1.0")
+	failUpgradePhase(errors.New("network blip"))
+
+	setUpgradePhase(UpgradePhaseDownloading, "v1.0.0", "v1.2.0")
+	status := u.UpgradeStatus()
+	if status.LastError != "" {
+		t.Fatalf("expected a fresh run to clear the prior error, got %q", status.LastError)
+	}
+	if status.TargetVersion != "v1.2.0" {
+		t.Fatalf("expected target version to update, got %q", status.TargetVersion)
+	}
+}
@@ -5,4 +5,24 @@ const (
 	Supervisor            = "supervisor"
 	SupervisorConfigPath  = "SupervisorConfigPath"
 	SupervisorServiceName = "SupervisorServiceName"
+	// SupervisorLogDir and SupervisorIncludeDir let an operator move
+	// supervisor's managed process logs and per-process ini files off
+	// BaseDir (e.g. onto external storage), defaulting to
+	// BaseDir/1panel/tools/supervisord/{log,supervisor.d} when unset.
+	SupervisorLogDir     = "SupervisorLogDir"
+	SupervisorIncludeDir = "SupervisorIncludeDir"
+
+	// SupervisordBinary and SupervisorctlBinary let an operator point at an
+	// install outside PATH (e.g. a Python venv or /opt), defaulting to a
+	// plain PATH lookup of "supervisord"/"supervisorctl" when unset.
+	SupervisordBinary   = "SupervisordBinary"
+	SupervisorctlBinary = "SupervisorctlBinary"
+
+	ServiceCustomCommands = "ServiceCustomCommands"
+
+	// SupervisorInetServerURL is the supervisorctl-style serverurl
+	// (http://host:port) of the [inet_http_server] most recently configured
+	// via SetSupervisorInetConfig, so the XML-RPC client can reach it without
+	// re-parsing supervisord.conf.
+	SupervisorInetServerURL = "SupervisorInetServerURL"
 )
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path"
 	"strings"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/1Panel-dev/1Panel/backend/global"
 	"github.com/1Panel-dev/1Panel/backend/utils/cloud_storage/client"
 	"github.com/go-gormigrate/gormigrate/v2"
+	"gopkg.in/ini.v1"
 	"gorm.io/gorm"
 )
 
@@ -452,6 +454,53 @@ var UpdateSnapshotRecords = &gormigrate.Migration{
 	},
 }
 
+// GroupSupervisorProcesses namespaces every existing supervisor-managed
+// process under a single `[group:1panel]` section, so they can be
+// started/stopped together through supervisorctl instead of only one at a
+// time. It's a no-op if there are no managed processes yet, or if the group
+// has already been written by a previous run.
+var GroupSupervisorProcesses = &gormigrate.Migration{
+	ID: "20240206-group-supervisor-processes",
+	Migrate: func(tx *gorm.DB) error {
+		includeDir := path.Join(global.CONF.System.BaseDir, "1panel", "tools", "supervisord", "supervisor.d")
+		groupPath := path.Join(includeDir, "1panel-group.ini")
+		if _, err := os.Stat(groupPath); err == nil {
+			return nil
+		}
+		entries, err := os.ReadDir(includeDir)
+		if err != nil {
+			return nil
+		}
+		var programs []string
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ini") {
+				continue
+			}
+			programs = append(programs, strings.TrimSuffix(entry.Name(), ".ini"))
+		}
+		if len(programs) == 0 {
+			return nil
+		}
+		cfg := ini.Empty()
+		section, err := cfg.NewSection("group:1panel")
+		if err != nil {
+			return err
+		}
+		_, _ = section.NewKey("programs", strings.Join(programs, ","))
+		return cfg.SaveTo(groupPath)
+	},
+}
+
+// AddServiceActionLog creates the table backing the service action audit
+// log (start/stop/restart/enable/disable/upgrade/rollback), separate from
+// the generic per-request OperationLog.
+var AddServiceActionLog = &gormigrate.Migration{
+	ID: "20240207-add-service-action-log",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&model.ServiceActionLog{})
+	},
+}
+
 var UpdateWebDavConf = &gormigrate.Migration{
 	ID: "20240205-update-webdav-conf",
 	Migrate: func(tx *gorm.DB) error {
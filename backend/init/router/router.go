@@ -6,6 +6,7 @@ import (
 	"github.com/1Panel-dev/1Panel/backend/i18n"
 	"github.com/1Panel-dev/1Panel/backend/middleware"
 	rou "github.com/1Panel-dev/1Panel/backend/router"
+	"github.com/1Panel-dev/1Panel/backend/utils/systemctl"
 	"github.com/1Panel-dev/1Panel/cmd/server/docs"
 	"github.com/1Panel-dev/1Panel/cmd/server/web"
 	"github.com/gin-contrib/gzip"
@@ -59,6 +60,10 @@ func Routers() *gin.Engine {
 	PublicGroup := Router.Group("")
 	{
 		PublicGroup.GET("/health", func(c *gin.Context) {
+			if err := systemctl.HealthCheck(); err != nil {
+				c.JSON(200, gin.H{"status": "degraded", "detail": err.Error()})
+				return
+			}
 			c.JSON(200, "ok")
 		})
 		PublicGroup.Use(gzip.Gzip(gzip.DefaultCompression))
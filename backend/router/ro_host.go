@@ -52,12 +52,32 @@ func (s *HostRouter) InitRouter(Router *gin.RouterGroup) {
 		hostRouter.POST("/command/update", baseApi.UpdateCommand)
 
 		hostRouter.POST("/tool", baseApi.GetToolStatus)
+		hostRouter.GET("/tool/init-systems", baseApi.GetInitSystems)
 		hostRouter.POST("/tool/init", baseApi.InitToolConfig)
 		hostRouter.POST("/tool/operate", baseApi.OperateTool)
 		hostRouter.POST("/tool/config", baseApi.OperateToolConfig)
+		hostRouter.POST("/tool/supervisor/inet", baseApi.SetSupervisorInetConfig)
+		hostRouter.POST("/tool/supervisor/binaries", baseApi.SetSupervisorBinaries)
+		hostRouter.POST("/tool/supervisor/reload", baseApi.OperateSupervisorReload)
+		hostRouter.POST("/tool/command", baseApi.SetServiceCustomCommand)
+		hostRouter.POST("/tool/service/names", baseApi.GetServiceNames)
+		hostRouter.GET("/tool/service/status", baseApi.GetServiceStatus)
+		hostRouter.POST("/tool/service/find", baseApi.FindServices)
+		hostRouter.POST("/tool/service/pin", baseApi.PinServiceName)
+		hostRouter.DELETE("/tool/service/pin", baseApi.UnpinServiceName)
+		hostRouter.POST("/tool/service/alias", baseApi.AddServiceAlias)
 		hostRouter.POST("/tool/log", baseApi.GetToolLog)
 		hostRouter.POST("/tool/supervisor/process", baseApi.OperateProcess)
+		hostRouter.POST("/tool/supervisor/process/test", baseApi.TestProcessCommand)
 		hostRouter.GET("/tool/supervisor/process", baseApi.GetProcess)
+		hostRouter.GET("/tool/supervisor/process/template", baseApi.GetProcessTemplates)
+		hostRouter.POST("/tool/supervisor/eventlistener", baseApi.OperateEventListener)
+		hostRouter.GET("/tool/supervisor/eventlistener", baseApi.GetEventListeners)
 		hostRouter.POST("/tool/supervisor/process/file", baseApi.GetProcessFile)
+		hostRouter.POST("/tool/supervisor/process/file/validate", baseApi.ValidateProcessConfig)
+		hostRouter.POST("/tool/supervisor/process/export", baseApi.ExportProcessConfigs)
+		hostRouter.POST("/tool/supervisor/process/import", baseApi.ImportProcessConfigs)
+		hostRouter.GET("/tool/supervisor/process/log/download", baseApi.DownloadProcessLog)
+		hostRouter.GET("/tool/supervisor/process/log/ws", baseApi.FollowProcessLog)
 	}
 }
@@ -59,8 +59,13 @@ func (s *SettingRouter) InitRouter(Router *gin.RouterGroup) {
 		settingRouter.POST("/backup/record/del", baseApi.DeleteBackupRecord)
 
 		settingRouter.POST("/upgrade", baseApi.Upgrade)
+		settingRouter.POST("/upgrade/cancel", baseApi.CancelUpgrade)
+		settingRouter.POST("/upgrade/proxy", baseApi.SetUpgradeProxy)
 		settingRouter.POST("/upgrade/notes", baseApi.GetNotesByVersion)
 		settingRouter.GET("/upgrade", baseApi.GetUpgradeInfo)
+		settingRouter.GET("/upgrade/check", baseApi.CheckUpgrade)
+		settingRouter.GET("/upgrade/versions", baseApi.ListVersions)
+		settingRouter.GET("/upgrade/status", baseApi.GetUpgradeStatus)
 		settingRouter.GET("/basedir", baseApi.LoadBaseDir)
 	}
 }
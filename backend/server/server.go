@@ -26,6 +26,7 @@ import (
 	"github.com/1Panel-dev/1Panel/backend/init/router"
 	"github.com/1Panel-dev/1Panel/backend/init/validator"
 	"github.com/1Panel-dev/1Panel/backend/init/viper"
+	"github.com/1Panel-dev/1Panel/backend/utils/servicectl"
 
 	"github.com/gin-gonic/gin"
 )
@@ -45,6 +46,15 @@ func Start() {
 	cron.Run()
 	business.Init()
 	hook.Init()
+	if err := servicectl.LoadAliases(); err != nil {
+		global.LOG.Errorf("load service alias cache failed, err: %v", err)
+	}
+	if err := servicectl.LoadPins(); err != nil {
+		global.LOG.Errorf("load service alias pins failed, err: %v", err)
+	}
+	if err := servicectl.LoadUserAliases(); err != nil {
+		global.LOG.Errorf("load user service aliases failed, err: %v", err)
+	}
 	InitOthers()
 
 	rootRouter := router.Routers()
@@ -37,6 +37,7 @@ func ExecWithTimeOut(cmdStr string, timeout time.Duration) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Start(); err != nil {
+		logCommandDebug(cmdStr, "", err)
 		return "", err
 	}
 	done := make(chan error, 1)
@@ -47,13 +48,17 @@ func ExecWithTimeOut(cmdStr string, timeout time.Duration) (string, error) {
 	select {
 	case <-after:
 		_ = cmd.Process.Kill()
+		logCommandDebug(cmdStr, "", buserr.New(constant.ErrCmdTimeout))
 		return "", buserr.New(constant.ErrCmdTimeout)
 	case err := <-done:
 		if err != nil {
-			return handleErr(stdout, stderr, err)
+			out, handledErr := handleErr(stdout, stderr, err)
+			logCommandDebug(cmdStr, out, handledErr)
+			return out, handledErr
 		}
 	}
 
+	logCommandDebug(cmdStr, stdout.String(), nil)
 	return stdout.String(), nil
 }
 
@@ -101,7 +106,28 @@ func ExecCronjobWithTimeOut(cmdStr, workdir, outPath string, timeout time.Durati
 }
 
 func Execf(cmdStr string, a ...interface{}) (string, error) {
+	fullCmd := fmt.Sprintf(cmdStr, a...)
+	cmd := exec.Command("bash", "-c", fullCmd)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		out, handledErr := handleErr(stdout, stderr, err)
+		logCommandDebug(fullCmd, out, handledErr)
+		return out, handledErr
+	}
+	logCommandDebug(fullCmd, stdout.String(), nil)
+	return stdout.String(), nil
+}
+
+// ExecfWithEnv behaves like Execf, but appends extraEnv on top of the
+// current process's environment before running the command, for callers
+// that need to force specific variables (e.g. LANG=C so a status probe's
+// output stays parseable regardless of the host's configured locale).
+func ExecfWithEnv(extraEnv []string, cmdStr string, a ...interface{}) (string, error) {
 	cmd := exec.Command("bash", "-c", fmt.Sprintf(cmdStr, a...))
+	cmd.Env = append(os.Environ(), extraEnv...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func withCapturedCommandDebugLog(t *testing.T) *[]string {
+	t.Helper()
+	var captured []string
+	orig := logCommandDebug
+	logCommandDebug = func(cmdStr, output string, err error) {
+		if !DebugLoggingEnabled() {
+			return
+		}
+		captured = append(captured, cmdStr)
+	}
+	t.Cleanup(func() { logCommandDebug = orig })
+	return &captured
+}
+
+func TestDebugLoggingEnabledReadsEnvVar(t *testing.T) {
+	t.Setenv(DebugLoggingEnvVar, "1")
+	if !DebugLoggingEnabled() {
+		t.Fatal("expected DebugLoggingEnabled to be true when the env var is \"1\"")
+	}
+	t.Setenv(DebugLoggingEnvVar, "0")
+	if DebugLoggingEnabled() {
+		t.Fatal("expected DebugLoggingEnabled to be false when the env var is not \"1\"")
+	}
+}
+
+func TestExecfLogsCommandWhenDebugEnabled(t *testing.T) {
+	captured := withCapturedCommandDebugLog(t)
+	t.Setenv(DebugLoggingEnvVar, "1")
+
+	if _, err := Execf("echo hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*captured) != 1 || (*captured)[0] != "echo hello" {
+		t.Fatalf("expected the command to be logged once, got %v", *captured)
+	}
+}
+
+func TestExecfDoesNotLogCommandWhenDebugDisabled(t *testing.T) {
+	captured := withCapturedCommandDebugLog(t)
+	t.Setenv(DebugLoggingEnvVar, "0")
+
+	if _, err := Execf("echo hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*captured) != 0 {
+		t.Fatalf("expected no logging when debug mode is off, got %v", *captured)
+	}
+}
+
+func TestExecfWithEnvAppliesExtraEnv(t *testing.T) {
+	out, err := ExecfWithEnv([]string{"LANG=C", "LC_ALL=C"}, "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "LANG=C") || !strings.Contains(out, "LC_ALL=C") {
+		t.Fatalf("expected spawned command's environment to include the forced locale vars, got: %s", out)
+	}
+}
+
+func TestExecfWithEnvInheritsCurrentEnvironment(t *testing.T) {
+	t.Setenv("CMD_TEST_INHERITED_VAR", "present")
+	out, err := ExecfWithEnv([]string{"LANG=C"}, "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "CMD_TEST_INHERITED_VAR=present") {
+		t.Fatalf("expected spawned command to inherit the parent process's environment, got: %s", out)
+	}
+}
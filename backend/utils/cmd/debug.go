@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/1Panel-dev/1Panel/backend/global"
+)
+
+// DebugLoggingEnvVar, when set to "1", makes Execf and ExecWithTimeOut log
+// every command string they run and its exit/output at debug level. This is
+// for diagnosing environment-specific init-system failures (e.g. a report
+// of "systemctl: command not found") where the exact command run and what
+// it actually returned aren't otherwise visible anywhere above the bare
+// error returned to the caller. Off by default to avoid flooding logs with
+// routine command output.
+const DebugLoggingEnvVar = "WRT_SYSTEMCTL_DEBUG"
+
+// DebugLoggingEnabled reports whether DebugLoggingEnvVar is set to "1".
+func DebugLoggingEnabled() bool {
+	return os.Getenv(DebugLoggingEnvVar) == "1"
+}
+
+// logCommandDebug logs cmdStr and its outcome when DebugLoggingEnabled, so
+// an operator chasing an environment-specific failure can see the exact
+// command and output without reproducing it by hand. It's a var, not a
+// direct global.LOG call, so tests can capture what would be logged
+// without needing global.LOG initialized outside the running server.
+var logCommandDebug = func(cmdStr, output string, err error) {
+	if !DebugLoggingEnabled() {
+		return
+	}
+	global.LOG.Debugf("exec %q output=%q err=%v", cmdStr, output, err)
+}
@@ -0,0 +1,359 @@
+package servicectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/1Panel-dev/1Panel/backend/constant"
+)
+
+// aliasFile is where the alias cache is persisted across restarts. It's set
+// deterministically at package init rather than lazily on first save, so
+// LoadAliases always knows where to look even if nothing has been resolved
+// yet this run.
+var aliasFile = path.Join(constant.ResourceDir, "svcaliases.json")
+
+// aliasSaveDebounce bounds how often the alias cache is flushed to disk when
+// resolutions happen in a burst (e.g. a batch GetServiceNames call).
+const aliasSaveDebounce = 20 * time.Second
+
+// aliasCache maps a keyword (e.g. "nginx") to the service name resolved for
+// it on this host, so repeated lookups for the same keyword don't re-probe
+// the system every time.
+var (
+	aliasCacheMu   sync.RWMutex
+	aliasCache     = map[string]string{}
+	aliasSaveTimer *time.Timer
+)
+
+// predefinedAliases maps a keyword to the extra candidate unit names worth
+// trying when the keyword itself isn't a valid systemd unit, for services
+// that commonly register under a distro-specific name (e.g. a clamd@scan
+// instance unit rather than a plain "clamav" service).
+var predefinedAliases = map[string][]string{
+	"clamav":     {"clamav-daemon", "clamd@scan", "clamd"},
+	"mysql":      {"mysqld", "mariadb"},
+	"postgresql": {"postgresql", "postgres"},
+	"docker":     {"docker", "containerd"},
+}
+
+// userAliasFile persists operator-added candidate names per keyword,
+// loaded and merged alongside predefinedAliases by getAliases. It's kept
+// separate from aliasFile (the resolved-name cache) for the same reason
+// pinFile is: a discovery cache cleanup must never wipe what an operator
+// configured.
+var userAliasFile = path.Join(constant.ResourceDir, "svcaliases_user.json")
+
+var (
+	userAliasMu sync.RWMutex
+	userAliases = map[string][]string{}
+)
+
+// LoadUserAliases reads previously saved user aliases from userAliasFile
+// into memory. A missing file just means none have been added yet.
+func LoadUserAliases() error {
+	data, err := os.ReadFile(userAliasFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := map[string][]string{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return err
+	}
+	userAliasMu.Lock()
+	userAliases = loaded
+	userAliasMu.Unlock()
+	return nil
+}
+
+// getAliases returns every candidate unit name worth trying for keyword,
+// beyond the keyword itself: operator-added aliases first (they're the
+// more deliberate, host-specific choice), then the hard-coded predefined
+// ones.
+func getAliases(keyword string) []string {
+	userAliasMu.RLock()
+	user := append([]string(nil), userAliases[keyword]...)
+	userAliasMu.RUnlock()
+	return append(user, predefinedAliases[keyword]...)
+}
+
+// confirmServiceExists reports whether name is a real service on this
+// host, swallowing the exists check's error the same way a failed lookup
+// just means "no" rather than something worth propagating.
+func confirmServiceExists(name string, exists func(string) (bool, error)) bool {
+	ok, err := exists(name)
+	return err == nil && ok
+}
+
+// AddPredefinedAlias validates each of names against exists and persists
+// the ones that are real services as extra candidates for keyword, merged
+// alongside the hard-coded predefinedAliases by getAliases. Invalid names
+// are silently dropped rather than failing the whole call, so one typo in
+// a batch doesn't block the rest.
+func AddPredefinedAlias(keyword string, names []string, exists func(string) (bool, error)) error {
+	userAliasMu.Lock()
+	defer userAliasMu.Unlock()
+	existing := userAliases[keyword]
+	seen := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		seen[name] = true
+	}
+	for _, name := range names {
+		if seen[name] || !confirmServiceExists(name, exists) {
+			continue
+		}
+		existing = append(existing, name)
+		seen[name] = true
+	}
+	userAliases[keyword] = existing
+	data, err := json.Marshal(userAliases)
+	if err != nil {
+		return err
+	}
+	tmp := userAliasFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, userAliasFile)
+}
+
+// cleanupKeywordAliases drops keyword's entry from the resolved-name
+// cache, forcing the next lookup to re-run discovery instead of reusing a
+// stale result. It only touches aliasCache, never userAliases or
+// pinCache, so an operator's pins and added aliases survive a cleanup.
+func cleanupKeywordAliases(keyword string) {
+	aliasCacheMu.Lock()
+	delete(aliasCache, keyword)
+	scheduleSave()
+	aliasCacheMu.Unlock()
+}
+
+// CleanupKeywordAlias is the exported form of cleanupKeywordAliases, for
+// callers outside this package that need to force re-discovery of a
+// keyword (e.g. after a service was reinstalled under the same keyword).
+func CleanupKeywordAlias(keyword string) {
+	cleanupKeywordAliases(keyword)
+}
+
+// LoadAliases reads the persisted alias cache from aliasFile into memory. It
+// should be called once during manager initialization; a missing file just
+// means no aliases have been discovered yet and isn't an error.
+func LoadAliases() error {
+	data, err := os.ReadFile(aliasFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := map[string]string{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return err
+	}
+	aliasCacheMu.Lock()
+	aliasCache = loaded
+	aliasCacheMu.Unlock()
+	return nil
+}
+
+// FlushAliases forces an immediate, synchronous save of the alias cache,
+// bypassing the debounce. Call this on graceful shutdown so a pending
+// debounced write isn't lost.
+func FlushAliases() error {
+	aliasCacheMu.Lock()
+	if aliasSaveTimer != nil {
+		aliasSaveTimer.Stop()
+		aliasSaveTimer = nil
+	}
+	data, err := json.Marshal(aliasCache)
+	aliasCacheMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeAliasFile(data)
+}
+
+// scheduleSave debounces writes to aliasFile so a burst of newly resolved
+// aliases results in one save instead of one per resolution. Caller must
+// hold aliasCacheMu.
+func scheduleSave() {
+	if aliasSaveTimer != nil {
+		return
+	}
+	aliasSaveTimer = time.AfterFunc(aliasSaveDebounce, func() {
+		aliasCacheMu.Lock()
+		aliasSaveTimer = nil
+		data, err := json.Marshal(aliasCache)
+		aliasCacheMu.Unlock()
+		if err != nil {
+			return
+		}
+		_ = writeAliasFile(data)
+	})
+}
+
+// writeAliasFile saves data to aliasFile via a temp-file-then-rename so a
+// crash mid-write can never leave a corrupt file behind.
+func writeAliasFile(data []byte) error {
+	tmp := aliasFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, aliasFile)
+}
+
+// candidateOrder returns every name worth trying to resolve keyword to,
+// most to least preferred: the keyword itself first, then each alias from
+// getAliases(keyword) (operator-added before predefined). This is the
+// priority order validateCandidatesConcurrently selects by.
+func candidateOrder(keyword string) []string {
+	return append([]string{keyword}, getAliases(keyword)...)
+}
+
+// candidateResult is one candidate's outcome from a concurrent resolve.
+type candidateResult struct {
+	name string
+	err  error
+}
+
+// selectBestMatch returns the resolved name of the first (highest-priority)
+// entry in results -- given in priority order, not completion order -- that
+// resolved without error. Completion order is irrelevant to the outcome:
+// whichever goroutine in validateCandidatesConcurrently finishes last still
+// loses to an earlier, successfully-resolved candidate.
+func selectBestMatch(results []candidateResult) (string, error) {
+	for _, r := range results {
+		if r.err == nil {
+			return r.name, nil
+		}
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("no candidates to resolve")
+	}
+	return "", results[0].err
+}
+
+// candidateOutcome carries a candidateResult back to
+// validateCandidatesConcurrently's collection loop tagged with the
+// candidate's priority index, since outcomes arrive in completion order,
+// not priority order.
+type candidateOutcome struct {
+	index int
+	candidateResult
+}
+
+// validateCandidatesConcurrently resolves every candidate in parallel --
+// resolve is usually a shell exec, so checking candidates serially pays
+// their combined latency even though only one can win -- and returns as
+// soon as a deterministic winner is known: once every candidate ranked
+// ahead of the best confirmed one so far has reported in, no still-running
+// goroutine could possibly outrank it, so there's no reason to wait for
+// stragglers. ctx is cancelled at that point so any candidate whose
+// goroutine hasn't started its resolve call yet skips it instead of running
+// a now-pointless exec.
+func validateCandidatesConcurrently(candidates []string, resolve func(string) (string, error)) (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outcomes := make(chan candidateOutcome, len(candidates))
+	for i, candidate := range candidates {
+		go func(i int, candidate string) {
+			if ctx.Err() != nil {
+				outcomes <- candidateOutcome{index: i, candidateResult: candidateResult{err: ctx.Err()}}
+				return
+			}
+			name, err := resolve(candidate)
+			outcomes <- candidateOutcome{index: i, candidateResult: candidateResult{name: name, err: err}}
+		}(i, candidate)
+	}
+
+	results := make([]*candidateResult, len(candidates))
+	for reported := 0; reported < len(candidates); reported++ {
+		o := <-outcomes
+		r := o.candidateResult
+		results[o.index] = &r
+		if winner, ok := decidedWinner(results); ok {
+			cancel()
+			return winner.name, nil
+		}
+	}
+	return selectBestMatch(flattenResults(results))
+}
+
+// decidedWinner reports the earliest confirmed candidate in results, but
+// only once every higher-priority candidate has already reported in --
+// until then a still-pending higher-priority candidate could still beat it.
+func decidedWinner(results []*candidateResult) (candidateResult, bool) {
+	for _, r := range results {
+		if r == nil {
+			return candidateResult{}, false
+		}
+		if r.err == nil {
+			return *r, true
+		}
+	}
+	return candidateResult{}, false
+}
+
+// flattenResults converts every non-nil entry of results (all of them, by
+// the time this is called) into the plain slice selectBestMatch expects.
+func flattenResults(results []*candidateResult) []candidateResult {
+	flat := make([]candidateResult, len(results))
+	for i, r := range results {
+		if r != nil {
+			flat[i] = *r
+		}
+	}
+	return flat
+}
+
+// ResolveServiceName returns the cached service name for keyword, resolving
+// and caching it on a miss. Every candidate from candidateOrder (the
+// keyword itself, then its aliases in preference order) is checked
+// concurrently via validateCandidatesConcurrently, but the candidate chosen
+// is always the highest-priority one that resolved, regardless of which
+// finished first -- so a service registered under a distro-specific unit
+// name still resolves, deterministically preferring the canonical name.
+func ResolveServiceName(keyword string, resolve func(string) (string, error)) (string, error) {
+	if pinned, ok := lookupPin(keyword); ok {
+		return pinned, nil
+	}
+
+	aliasCacheMu.RLock()
+	name, ok := aliasCache[keyword]
+	aliasCacheMu.RUnlock()
+	if ok {
+		return name, nil
+	}
+
+	name, err := validateCandidatesConcurrently(candidateOrder(keyword), resolve)
+	if err != nil {
+		return "", err
+	}
+	aliasCacheMu.Lock()
+	aliasCache[keyword] = name
+	scheduleSave()
+	aliasCacheMu.Unlock()
+	return name, nil
+}
+
+// GetServiceNames resolves every keyword in one pass, warming the alias
+// cache for each. A keyword that fails to resolve is omitted from the
+// result rather than failing the whole batch.
+func GetServiceNames(keywords []string, resolve func(string) (string, error)) map[string]string {
+	result := make(map[string]string, len(keywords))
+	for _, keyword := range keywords {
+		if name, err := ResolveServiceName(keyword, resolve); err == nil {
+			result[keyword] = name
+		}
+	}
+	return result
+}
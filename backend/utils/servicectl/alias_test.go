@@ -0,0 +1,317 @@
+package servicectl
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestResolveServiceNameCachesResult(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	calls := 0
+	resolve := func(keyword string) (string, error) {
+		calls++
+		return keyword + ".service", nil
+	}
+
+	name, err := ResolveServiceName("nginx", resolve)
+	if err != nil || name != "nginx.service" {
+		t.Fatalf("unexpected result: %q, %v", name, err)
+	}
+	if _, err = ResolveServiceName("nginx", resolve); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected resolve to be called once due to caching, got %d calls", calls)
+	}
+}
+
+func TestGetServiceNamesSkipsFailures(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	resolve := func(keyword string) (string, error) {
+		if keyword == "bad" {
+			return "", errors.New("not found")
+		}
+		return keyword + ".service", nil
+	}
+
+	result := GetServiceNames([]string{"nginx", "bad"}, resolve)
+	if len(result) != 1 || result["nginx"] != "nginx.service" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestGetAliasesMergesUserAndPredefinedWithUserFirst(t *testing.T) {
+	userAliasMu.Lock()
+	userAliases = map[string][]string{"clamav": {"clamav-custom"}}
+	userAliasMu.Unlock()
+	defer func() {
+		userAliasMu.Lock()
+		userAliases = map[string][]string{}
+		userAliasMu.Unlock()
+	}()
+
+	got := getAliases("clamav")
+	want := []string{"clamav-custom", "clamav-daemon", "clamd@scan", "clamd"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected aliases: %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected aliases: %v", got)
+		}
+	}
+}
+
+func TestConfirmServiceExists(t *testing.T) {
+	exists := func(name string) (bool, error) {
+		if name == "real.service" {
+			return true, nil
+		}
+		return false, errors.New("not found")
+	}
+	if !confirmServiceExists("real.service", exists) {
+		t.Fatal("expected real.service to be confirmed")
+	}
+	if confirmServiceExists("fake.service", exists) {
+		t.Fatal("expected fake.service to be rejected")
+	}
+}
+
+func TestAddPredefinedAliasPersistsValidNamesAndDedupes(t *testing.T) {
+	origFile := userAliasFile
+	userAliasFile = path.Join(t.TempDir(), "svcaliases_user.json")
+	defer func() { userAliasFile = origFile }()
+
+	userAliasMu.Lock()
+	userAliases = map[string][]string{}
+	userAliasMu.Unlock()
+
+	exists := func(name string) (bool, error) { return name != "bogus", nil }
+	if err := AddPredefinedAlias("redis", []string{"redis-server", "bogus"}, exists); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddPredefinedAlias("redis", []string{"redis-server", "redis6"}, exists); err != nil {
+		t.Fatal(err)
+	}
+
+	userAliasMu.RLock()
+	got := append([]string(nil), userAliases["redis"]...)
+	userAliasMu.RUnlock()
+	want := []string{"redis-server", "redis6"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("unexpected persisted aliases: %v", got)
+	}
+
+	userAliasMu.Lock()
+	userAliases = map[string][]string{}
+	userAliasMu.Unlock()
+	if err := LoadUserAliases(); err != nil {
+		t.Fatal(err)
+	}
+	userAliasMu.RLock()
+	got = userAliases["redis"]
+	userAliasMu.RUnlock()
+	if len(got) != 2 {
+		t.Fatalf("expected persisted aliases to survive reload, got %v", got)
+	}
+}
+
+func TestResolveServiceNameFallsBackThroughUserAlias(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+	userAliasMu.Lock()
+	userAliases = map[string][]string{"queue": {"rabbitmq-server"}}
+	userAliasMu.Unlock()
+	defer func() {
+		userAliasMu.Lock()
+		userAliases = map[string][]string{}
+		userAliasMu.Unlock()
+	}()
+
+	resolve := func(keyword string) (string, error) {
+		if keyword == "rabbitmq-server" {
+			return "rabbitmq-server.service", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	name, err := ResolveServiceName("queue", resolve)
+	if err != nil || name != "rabbitmq-server.service" {
+		t.Fatalf("expected resolution via user alias, got %q, %v", name, err)
+	}
+}
+
+func TestCleanupKeywordAliasClearsOnlyResolvedCache(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{"nginx": "nginx.service"}
+	aliasCacheMu.Unlock()
+	userAliasMu.Lock()
+	userAliases = map[string][]string{"nginx": {"nginx-extra"}}
+	userAliasMu.Unlock()
+	defer func() {
+		userAliasMu.Lock()
+		userAliases = map[string][]string{}
+		userAliasMu.Unlock()
+	}()
+
+	CleanupKeywordAlias("nginx")
+
+	aliasCacheMu.RLock()
+	_, ok := aliasCache["nginx"]
+	aliasCacheMu.RUnlock()
+	if ok {
+		t.Fatal("expected resolved-name cache entry to be cleared")
+	}
+	userAliasMu.RLock()
+	_, ok = userAliases["nginx"]
+	userAliasMu.RUnlock()
+	if !ok {
+		t.Fatal("cleanup must not remove operator-added aliases")
+	}
+}
+
+func TestFlushAndLoadAliases(t *testing.T) {
+	origFile := aliasFile
+	aliasFile = path.Join(t.TempDir(), "svcaliases.json")
+	defer func() { aliasFile = origFile }()
+
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{"nginx": "nginx.service"}
+	aliasCacheMu.Unlock()
+
+	if err := FlushAliases(); err != nil {
+		t.Fatal(err)
+	}
+
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	if err := LoadAliases(); err != nil {
+		t.Fatal(err)
+	}
+
+	aliasCacheMu.RLock()
+	name := aliasCache["nginx"]
+	aliasCacheMu.RUnlock()
+	if name != "nginx.service" {
+		t.Fatalf("expected loaded alias cache to contain nginx.service, got %q", name)
+	}
+}
+
+func TestSelectBestMatchPrefersEarliestConfirmedRegardlessOfOrder(t *testing.T) {
+	results := []candidateResult{
+		{err: errors.New("not found")},
+		{name: "second-choice"},
+		{name: "third-choice"},
+	}
+	name, err := selectBestMatch(results)
+	if err != nil || name != "second-choice" {
+		t.Fatalf("expected the first confirmed candidate to win, got %q, %v", name, err)
+	}
+}
+
+func TestSelectBestMatchReturnsFirstErrorWhenNoneConfirm(t *testing.T) {
+	wantErr := errors.New("keyword not found")
+	results := []candidateResult{{err: wantErr}, {err: errors.New("alias not found")}}
+	_, err := selectBestMatch(results)
+	if err != wantErr {
+		t.Fatalf("expected the highest-priority candidate's own error, got %v", err)
+	}
+}
+
+// TestValidateCandidatesConcurrentlyPrefersPriorityOverCompletionOrder makes
+// the lowest-priority candidate resolve first (it has no artificial delay)
+// and the highest-priority one resolve slower, to prove the race winner
+// never matters -- only priority order does.
+func TestValidateCandidatesConcurrentlyPrefersPriorityOverCompletionOrder(t *testing.T) {
+	resolve := func(candidate string) (string, error) {
+		switch candidate {
+		case "sshd":
+			time.Sleep(30 * time.Millisecond)
+			return "sshd.service", nil
+		case "ssh":
+			return "ssh.service", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	for i := 0; i < 5; i++ {
+		name, err := validateCandidatesConcurrently([]string{"sshd", "ssh"}, resolve)
+		if err != nil || name != "sshd.service" {
+			t.Fatalf("expected the higher-priority candidate to win despite resolving slower, got %q, %v", name, err)
+		}
+	}
+}
+
+// TestValidateCandidatesConcurrentlyReturnsOnceWinnerIsDecidedWithoutWaitingStragglers
+// makes the highest-priority candidate resolve quickly and a lower-priority
+// one resolve much slower, and asserts the call returns close to the
+// winner's own latency rather than blocking for the full straggler delay --
+// proving a decided winner short-circuits instead of waiting out every
+// goroutine.
+func TestValidateCandidatesConcurrentlyReturnsOnceWinnerIsDecidedWithoutWaitingStragglers(t *testing.T) {
+	const stragglerDelay = 200 * time.Millisecond
+	resolve := func(candidate string) (string, error) {
+		if candidate == "ssh" {
+			return "ssh.service", nil
+		}
+		time.Sleep(stragglerDelay)
+		return "", errors.New("not found")
+	}
+
+	start := time.Now()
+	name, err := validateCandidatesConcurrently([]string{"ssh", "slow-alias"}, resolve)
+	elapsed := time.Since(start)
+
+	if err != nil || name != "ssh.service" {
+		t.Fatalf("unexpected result: %q, %v", name, err)
+	}
+	if elapsed >= stragglerDelay {
+		t.Fatalf("expected to return before the straggler's delay elapsed, took %v", elapsed)
+	}
+}
+
+func TestValidateCandidatesConcurrentlyFallsBackWhenHighestPriorityFails(t *testing.T) {
+	resolve := func(candidate string) (string, error) {
+		if candidate == "queue" {
+			return "", errors.New("not found")
+		}
+		return candidate + ".service", nil
+	}
+	name, err := validateCandidatesConcurrently([]string{"queue", "rabbitmq-server"}, resolve)
+	if err != nil || name != "rabbitmq-server.service" {
+		t.Fatalf("expected fallback to the next candidate, got %q, %v", name, err)
+	}
+}
+
+func TestCandidateOrderPutsKeywordFirstThenUserAliasesThenPredefined(t *testing.T) {
+	userAliasMu.Lock()
+	userAliases = map[string][]string{"clamav": {"clamav-custom"}}
+	userAliasMu.Unlock()
+	defer func() {
+		userAliasMu.Lock()
+		userAliases = map[string][]string{}
+		userAliasMu.Unlock()
+	}()
+
+	got := candidateOrder("clamav")
+	want := []string{"clamav", "clamav-custom", "clamav-daemon", "clamd@scan", "clamd"}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected candidate order: %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected candidate order: %v", got)
+		}
+	}
+}
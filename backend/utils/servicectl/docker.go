@@ -0,0 +1,67 @@
+package servicectl
+
+import (
+	"os"
+	"strings"
+
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+// dockerSocketPath is docker's default control socket. Its presence is a
+// strong signal dockerd is up even when no init-system unit exists to ask
+// about it at all (e.g. OpenWRT running dockerd under procd instead of
+// systemd). It's a var, not a const, so tests can point it at a fixture
+// file instead of the real path.
+var dockerSocketPath = "/var/run/docker.sock"
+
+// dockerdProcessRunning is a var, not a direct call, so tests can fake the
+// pgrep result without actually needing dockerd installed.
+var dockerdProcessRunning = func() bool {
+	out, err := cmd.Exec("pgrep -x dockerd")
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// activeFallbacks maps a keyword to an extra active/enabled check tried by
+// ResolveServiceActive only once every alias candidate from getAliases has
+// failed to resolve to a real unit under the host's init system. docker is
+// the first case of this: it's common for dockerd to run unmanaged by any
+// init system at all (procd on OpenWRT, or a manually started daemon), in
+// which case there's simply no unit name for resolve to ever succeed on.
+var activeFallbacks = map[string]func() (active, enabled bool){
+	"docker": dockerProcessFallback,
+}
+
+// dockerProcessFallback reports dockerd as active if its control socket
+// exists or a dockerd process is running. There's no separate boot-
+// enablement concept to query for a daemon with no init-system unit, so
+// enabled just mirrors active.
+func dockerProcessFallback() (active, enabled bool) {
+	if _, err := os.Stat(dockerSocketPath); err == nil {
+		return true, true
+	}
+	active = dockerdProcessRunning()
+	return active, active
+}
+
+// ResolveServiceActive reports whether keyword's service is active (and, on
+// the normal resolution path, enabled), using ResolveServiceName plus
+// isActive/isEnabled the same way the rest of this package resolves a
+// keyword to a real unit. Only when that resolution fails outright -- no
+// alias candidate matched anything under the host's init system -- does it
+// fall back to keyword's registered activeFallbacks check, if any.
+func ResolveServiceActive(keyword string, resolve func(string) (string, error), isActive, isEnabled func(string) (bool, error)) (active, enabled bool, err error) {
+	name, resolveErr := ResolveServiceName(keyword, resolve)
+	if resolveErr == nil {
+		active, err = isActive(name)
+		if err != nil {
+			return false, false, err
+		}
+		enabled, _ = isEnabled(name)
+		return active, enabled, nil
+	}
+	if fallback, ok := activeFallbacks[keyword]; ok {
+		active, enabled = fallback()
+		return active, enabled, nil
+	}
+	return false, false, resolveErr
+}
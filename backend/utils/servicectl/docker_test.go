@@ -0,0 +1,112 @@
+package servicectl
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func withDockerSocketPath(t *testing.T, p string) {
+	t.Helper()
+	orig := dockerSocketPath
+	dockerSocketPath = p
+	t.Cleanup(func() { dockerSocketPath = orig })
+}
+
+func withDockerdProcessRunning(t *testing.T, running bool) {
+	t.Helper()
+	orig := dockerdProcessRunning
+	dockerdProcessRunning = func() bool { return running }
+	t.Cleanup(func() { dockerdProcessRunning = orig })
+}
+
+func TestDockerProcessFallbackActiveViaSocket(t *testing.T) {
+	dir := t.TempDir()
+	sock := path.Join(dir, "docker.sock")
+	if err := os.WriteFile(sock, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	withDockerSocketPath(t, sock)
+	withDockerdProcessRunning(t, false)
+
+	active, enabled := dockerProcessFallback()
+	if !active || !enabled {
+		t.Fatalf("expected active and enabled when the socket exists, got active=%v enabled=%v", active, enabled)
+	}
+}
+
+func TestDockerProcessFallbackActiveViaProcess(t *testing.T) {
+	withDockerSocketPath(t, path.Join(t.TempDir(), "missing.sock"))
+	withDockerdProcessRunning(t, true)
+
+	active, enabled := dockerProcessFallback()
+	if !active || !enabled {
+		t.Fatalf("expected active and enabled when dockerd is running, got active=%v enabled=%v", active, enabled)
+	}
+}
+
+func TestDockerProcessFallbackInactiveWhenNeitherSignalPresent(t *testing.T) {
+	withDockerSocketPath(t, path.Join(t.TempDir(), "missing.sock"))
+	withDockerdProcessRunning(t, false)
+
+	active, enabled := dockerProcessFallback()
+	if active || enabled {
+		t.Fatalf("expected inactive when neither socket nor process is present, got active=%v enabled=%v", active, enabled)
+	}
+}
+
+func TestResolveServiceActiveUsesNormalResolutionWhenItSucceeds(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	resolve := func(keyword string) (string, error) { return keyword + ".service", nil }
+	isActive := func(name string) (bool, error) { return name == "docker.service", nil }
+	isEnabled := func(name string) (bool, error) { return true, nil }
+
+	active, enabled, err := ResolveServiceActive("docker", resolve, isActive, isEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active || !enabled {
+		t.Fatalf("expected active=true enabled=true, got active=%v enabled=%v", active, enabled)
+	}
+}
+
+func TestResolveServiceActiveFallsBackWhenResolutionFailsEntirely(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	withDockerSocketPath(t, path.Join(t.TempDir(), "missing.sock"))
+	withDockerdProcessRunning(t, true)
+
+	resolve := func(keyword string) (string, error) { return "", errors.New("not found") }
+	isActive := func(name string) (bool, error) { t.Fatal("isActive should not be called on the fallback path"); return false, nil }
+	isEnabled := func(name string) (bool, error) { t.Fatal("isEnabled should not be called on the fallback path"); return false, nil }
+
+	active, enabled, err := ResolveServiceActive("docker", resolve, isActive, isEnabled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active || !enabled {
+		t.Fatalf("expected the dockerd process fallback to report active, got active=%v enabled=%v", active, enabled)
+	}
+}
+
+func TestResolveServiceActiveReturnsErrorWhenNoFallbackRegistered(t *testing.T) {
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{}
+	aliasCacheMu.Unlock()
+
+	wantErr := errors.New("not found")
+	resolve := func(keyword string) (string, error) { return "", wantErr }
+	isActive := func(name string) (bool, error) { return false, nil }
+	isEnabled := func(name string) (bool, error) { return false, nil }
+
+	_, _, err := ResolveServiceActive("nginx", resolve, isActive, isEnabled)
+	if err == nil {
+		t.Fatal("expected an error for a keyword with no registered fallback")
+	}
+}
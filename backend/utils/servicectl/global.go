@@ -0,0 +1,77 @@
+package servicectl
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// globalManagerState pairs the Manager GetGlobalManager last built with the
+// customCommands it was built from, so a later call with the same
+// customCommands can reuse it instead of rebuilding on every operation.
+type globalManagerState struct {
+	manager  *Manager
+	commands map[string]map[string]string
+}
+
+// globalManagerValue holds the most recently built *globalManagerState
+// behind an atomic.Value instead of a plain variable guarded only by a
+// mutex, so GetGlobalManager never has to hold a lock across a read. A
+// lock-then-read pattern has a window where a concurrent reinitialization
+// can observe the field between being cleared and being repopulated; an
+// atomic.Value swap is always either the old state or the new one, never a
+// transient nil.
+var globalManagerValue atomic.Value // holds *globalManagerState
+
+// globalManagerInitMu serializes building the Manager so a burst of
+// concurrent calls with the same customCommands doesn't construct (and
+// discard) it more than once.
+var globalManagerInitMu sync.Mutex
+
+// buildGlobalManager builds the Manager GetGlobalManager should use for
+// customCommands; overridden in tests to simulate an init failure without
+// touching real on-disk custom command config.
+var buildGlobalManager = func(customCommands map[string]map[string]string) (*Manager, error) {
+	return NewManager(customCommands), nil
+}
+
+// GetGlobalManager returns the process-wide Manager for customCommands,
+// building it on first use and rebuilding it whenever customCommands has
+// changed since the last call (e.g. an operator just edited a custom
+// service command). It never panics: if buildGlobalManager fails or returns
+// nil, it falls back to a Manager with no custom commands configured, which
+// still operates services through the default registered backend, rather
+// than taking the whole server down over a config load that didn't work.
+func GetGlobalManager(customCommands map[string]map[string]string) *Manager {
+	if state, ok := globalManagerValue.Load().(*globalManagerState); ok && state != nil && reflect.DeepEqual(state.commands, customCommands) {
+		return state.manager
+	}
+	return initGlobalManager(customCommands)
+}
+
+func initGlobalManager(customCommands map[string]map[string]string) *Manager {
+	globalManagerInitMu.Lock()
+	defer globalManagerInitMu.Unlock()
+	if state, ok := globalManagerValue.Load().(*globalManagerState); ok && state != nil && reflect.DeepEqual(state.commands, customCommands) {
+		return state.manager
+	}
+	m := newManagerOrFallback(customCommands)
+	globalManagerValue.Store(&globalManagerState{manager: m, commands: customCommands})
+	return m
+}
+
+// ReinitializeGlobalManager forces the next GetGlobalManager call (and any
+// already in flight) to observe a freshly built Manager for customCommands,
+// e.g. after an operator edits custom service commands on disk.
+func ReinitializeGlobalManager(customCommands map[string]map[string]string) {
+	globalManagerInitMu.Lock()
+	defer globalManagerInitMu.Unlock()
+	globalManagerValue.Store(&globalManagerState{manager: newManagerOrFallback(customCommands), commands: customCommands})
+}
+
+func newManagerOrFallback(customCommands map[string]map[string]string) *Manager {
+	if m, err := buildGlobalManager(customCommands); err == nil && m != nil {
+		return m
+	}
+	return NewManager(nil)
+}
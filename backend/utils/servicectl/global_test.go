@@ -0,0 +1,86 @@
+package servicectl
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func resetGlobalManager() {
+	globalManagerValue.Store((*globalManagerState)(nil))
+	buildGlobalManager = func(customCommands map[string]map[string]string) (*Manager, error) {
+		return NewManager(customCommands), nil
+	}
+}
+
+func TestGetGlobalManagerBuildsOnFirstUse(t *testing.T) {
+	defer resetGlobalManager()
+	resetGlobalManager()
+
+	m := GetGlobalManager(nil)
+	if m == nil {
+		t.Fatal("expected a non-nil Manager")
+	}
+	if GetGlobalManager(nil) != m {
+		t.Fatal("expected repeated calls with the same customCommands to reuse the same Manager")
+	}
+}
+
+func TestGetGlobalManagerRebuildsWhenCustomCommandsChange(t *testing.T) {
+	defer resetGlobalManager()
+	resetGlobalManager()
+
+	first := GetGlobalManager(nil)
+	second := GetGlobalManager(map[string]map[string]string{"nginx": {"restart": "echo hi"}})
+	if first == second {
+		t.Fatal("expected a Manager built from different customCommands to be a new instance")
+	}
+	third := GetGlobalManager(map[string]map[string]string{"nginx": {"restart": "echo hi"}})
+	if second != third {
+		t.Fatal("expected repeated calls with the same customCommands to reuse the same Manager")
+	}
+}
+
+func TestGetGlobalManagerFallsBackWhenBuildFails(t *testing.T) {
+	defer resetGlobalManager()
+	resetGlobalManager()
+	buildGlobalManager = func(customCommands map[string]map[string]string) (*Manager, error) { return nil, errors.New("init failed") }
+
+	m := GetGlobalManager(nil)
+	if m == nil {
+		t.Fatal("expected a fallback Manager instead of nil")
+	}
+}
+
+func TestReinitializeGlobalManagerSwapsInstance(t *testing.T) {
+	defer resetGlobalManager()
+	resetGlobalManager()
+
+	first := GetGlobalManager(nil)
+	ReinitializeGlobalManager(nil)
+	second := GetGlobalManager(nil)
+	if first == second {
+		t.Fatal("expected reinitialization to produce a new Manager instance")
+	}
+}
+
+func TestGetGlobalManagerConcurrentWithReinitializeNeverPanicsOrNils(t *testing.T) {
+	defer resetGlobalManager()
+	resetGlobalManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if GetGlobalManager(nil) == nil {
+				t.Error("GetGlobalManager returned nil during concurrent reinitialization")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ReinitializeGlobalManager(nil)
+		}()
+	}
+	wg.Wait()
+}
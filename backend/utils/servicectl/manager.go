@@ -0,0 +1,279 @@
+package servicectl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/1Panel-dev/1Panel/backend/global"
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+	"github.com/1Panel-dev/1Panel/backend/utils/systemctl"
+	"github.com/pkg/errors"
+)
+
+// defaultBackendName is the Backend used when an operation doesn't request a
+// specific registered manager.
+const defaultBackendName = "systemd"
+
+// unsafeCommandChars matches shell metacharacters that must never appear in a
+// user-supplied custom service command, since custom commands are executed
+// through a shell rather than exec'd with a fixed argv like systemctl is.
+var unsafeCommandChars = regexp.MustCompile("[;&|` $><\n]")
+
+// Manager builds and runs the command for a service action, honouring any
+// per-service custom command configured by the operator and falling back to
+// the default systemctl-backed command otherwise.
+type Manager struct {
+	// customCommands maps serviceName -> action -> shell command.
+	customCommands map[string]map[string]string
+}
+
+func NewManager(customCommands map[string]map[string]string) *Manager {
+	return &Manager{customCommands: customCommands}
+}
+
+// ValidateCustomCommand rejects empty commands and commands containing shell
+// metacharacters, so a stored override can't be used to inject arbitrary
+// shell syntax onto the service it's scoped to.
+func ValidateCustomCommand(command string) error {
+	if command == "" {
+		return errors.New("command is empty")
+	}
+	if unsafeCommandChars.MatchString(command) {
+		return errors.New("command contains unsafe characters")
+	}
+	return nil
+}
+
+// Operate runs action against serviceName using the default registered
+// manager. If a custom command is configured for this exact service/action
+// pair it is used instead of the manager-built command.
+func (m *Manager) Operate(action, serviceName string) error {
+	return m.OperateWithManager(action, serviceName, "")
+}
+
+// OperateWithManager behaves like Operate, but when managerName is non-empty
+// it runs the action through that explicitly registered manager instead of
+// the default one, failing if the named manager isn't available.
+func (m *Manager) OperateWithManager(action, serviceName, managerName string) error {
+	if custom, ok := m.customCommand(serviceName, action); ok {
+		if err := ValidateCustomCommand(custom); err != nil {
+			return fmt.Errorf("custom command for %s/%s rejected: %w", serviceName, action, err)
+		}
+		_, err := cmd.Exec(custom)
+		recordOperation("custom", action, err)
+		return err
+	}
+	if managerName == "" {
+		managerName = defaultBackendName
+	}
+	backend, err := GetBackend(managerName)
+	if err != nil {
+		return err
+	}
+	serviceName = normalizeServiceName(managerName, action, serviceName)
+	err = backend.Operate(action, serviceName)
+	if isCommandNotFoundErr(err) {
+		if retryBackend, retryName, ok := reinitializeManager(managerName); ok {
+			err = retryBackend.Operate(action, normalizeServiceName(retryName, action, serviceName))
+			recordOperation(retryName, action, err)
+			return err
+		}
+	}
+	recordOperation(managerName, action, err)
+	return err
+}
+
+// OperateWithArgs behaves like OperateWithManager, but also passes extra CLI
+// flags through to the action (e.g. "restart" with "--no-block"). It fails
+// if managerName's backend doesn't support extra arguments; passing no args
+// falls back to the plain OperateWithManager behavior regardless.
+func (m *Manager) OperateWithArgs(action, serviceName string, args []string, managerName string) error {
+	if len(args) == 0 {
+		return m.OperateWithManager(action, serviceName, managerName)
+	}
+	if custom, ok := m.customCommand(serviceName, action); ok {
+		if err := ValidateCustomCommand(custom); err != nil {
+			return fmt.Errorf("custom command for %s/%s rejected: %w", serviceName, action, err)
+		}
+		_, err := cmd.Exec(custom)
+		recordOperation("custom", action, err)
+		return err
+	}
+	if managerName == "" {
+		managerName = defaultBackendName
+	}
+	backend, err := GetBackend(managerName)
+	if err != nil {
+		return err
+	}
+	argsBackend, ok := backend.(ArgsBackend)
+	if !ok {
+		return fmt.Errorf("service manager %q does not support extra action arguments", managerName)
+	}
+	serviceName = normalizeServiceName(managerName, action, serviceName)
+	err = argsBackend.OperateWithArgs(action, serviceName, args)
+	if isCommandNotFoundErr(err) {
+		if retryBackend, retryName, ok := reinitializeManager(managerName); ok {
+			if retryArgsBackend, ok := retryBackend.(ArgsBackend); ok {
+				err = retryArgsBackend.OperateWithArgs(action, normalizeServiceName(retryName, action, serviceName), args)
+				recordOperation(retryName, action, err)
+				return err
+			}
+		}
+	}
+	recordOperation(managerName, action, err)
+	return err
+}
+
+// ServiceResult is one action's outcome from CustomActions, in the order the
+// actions were requested.
+type ServiceResult struct {
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CustomActions runs actions against serviceName in sequence through the
+// same resolved backend, stopping at the first failure. It's meant for a
+// caller that chains several actions against one service (e.g. "restart"
+// then "enable" behind a single button), so the manager is only resolved
+// once instead of once per action. It returns the results gathered so far,
+// including the one that failed, so the caller can show exactly how far the
+// run got.
+func (m *Manager) CustomActions(serviceName string, actions []string, managerName string) ([]ServiceResult, error) {
+	if managerName == "" {
+		managerName = defaultBackendName
+	}
+	backend, err := GetBackend(managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ServiceResult, 0, len(actions))
+	for _, action := range actions {
+		opErr := m.runAction(backend, managerName, action, serviceName)
+		results = append(results, ServiceResult{Action: action, Error: errString(opErr)})
+		if opErr != nil {
+			return results, opErr
+		}
+	}
+	return results, nil
+}
+
+// runAction executes a single action against serviceName through backend,
+// honouring a per-service custom command override and retrying once against
+// a re-detected manager on a command-not-found error, the same way
+// OperateWithManager does for a single action.
+func (m *Manager) runAction(backend Backend, managerName, action, serviceName string) error {
+	if custom, ok := m.customCommand(serviceName, action); ok {
+		if err := ValidateCustomCommand(custom); err != nil {
+			return fmt.Errorf("custom command for %s/%s rejected: %w", serviceName, action, err)
+		}
+		_, err := cmd.Exec(custom)
+		recordOperation("custom", action, err)
+		return err
+	}
+	normalized := normalizeServiceName(managerName, action, serviceName)
+	err := backend.Operate(action, normalized)
+	if isCommandNotFoundErr(err) {
+		if retryBackend, retryName, ok := reinitializeManager(managerName); ok {
+			err = retryBackend.Operate(action, normalizeServiceName(retryName, action, serviceName))
+			recordOperation(retryName, action, err)
+			return err
+		}
+	}
+	recordOperation(managerName, action, err)
+	return err
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// normalizeServiceName adapts serviceName to the naming convention
+// managerName's backend expects for action, so a ".service"-suffixed name
+// meant for systemd doesn't leak into an openrc/sysvinit command (or vice
+// versa): systemd units always end in ".service" regardless of action,
+// every other backend uses the bare script/service name with no suffix.
+// action is accepted, not just the service name, because a future backend
+// (e.g. procd) may need a form that genuinely differs by action even on the
+// same manager; none of today's backends do.
+func normalizeServiceName(managerName, action, serviceName string) string {
+	switch managerName {
+	case "systemd":
+		if !strings.HasSuffix(serviceName, ".service") {
+			return serviceName + ".service"
+		}
+		return serviceName
+	default:
+		return strings.TrimSuffix(serviceName, ".service")
+	}
+}
+
+// commandNotFoundMarkers are substrings Go's exec package (and the shells it
+// invokes) use to report that a manager's CLI binary isn't on PATH. This
+// covers both Go's own "exec: ... executable file not found in $PATH" (the
+// case when the backend runs exec.Command directly, e.g. systemd) and a
+// plain shell's "command not found" (the case for custom and shelled-out
+// commands), since either can mean the manager that was detected earlier is
+// now stale.
+var commandNotFoundMarkers = []string{
+	"executable file not found",
+	"command not found",
+}
+
+func isCommandNotFoundErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range commandNotFoundMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// reinitializeManagerLookup re-detects the init system actually available on
+// this host; overridden in tests so the fallback path can be exercised
+// without depending on what's actually installed.
+var reinitializeManagerLookup = systemctl.ActiveManager
+
+// reinitializeManager re-detects the init system actually available on this
+// host and, if it differs from the manager that just failed (or wasn't
+// registered at all), returns its backend so the caller can retry the
+// operation once against it.
+func reinitializeManager(failedManagerName string) (Backend, string, bool) {
+	name, available := reinitializeManagerLookup()
+	if !available || name == failedManagerName {
+		return nil, "", false
+	}
+	backend, err := GetBackend(name)
+	if err != nil {
+		return nil, "", false
+	}
+	logManagerSwitch(failedManagerName, name)
+	return backend, name, true
+}
+
+// logManagerSwitch reports a manager re-detection fallback; overridden in
+// tests since global.LOG isn't initialized outside the running server.
+var logManagerSwitch = func(failedManagerName, detectedManagerName string) {
+	global.LOG.Warnf("service manager %q appears unavailable, switching to re-detected manager %q", failedManagerName, detectedManagerName)
+}
+
+func (m *Manager) customCommand(serviceName, action string) (string, bool) {
+	actions, ok := m.customCommands[serviceName]
+	if !ok {
+		return "", false
+	}
+	command, ok := actions[action]
+	if !ok || command == "" {
+		return "", false
+	}
+	return command, true
+}
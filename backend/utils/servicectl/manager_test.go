@@ -0,0 +1,300 @@
+package servicectl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateCustomCommand(t *testing.T) {
+	cases := []struct {
+		command string
+		wantErr bool
+	}{
+		{"/usr/local/bin/my-restart.sh", false},
+		{"", true},
+		{"restart.sh; rm -rf /", true},
+		{"restart.sh && reboot", true},
+		{"restart.sh | tee /tmp/out", true},
+		{"restart.sh `whoami`", true},
+		{"restart.sh $(whoami)", true},
+	}
+	for _, c := range cases {
+		err := ValidateCustomCommand(c.command)
+		if c.wantErr && err == nil {
+			t.Errorf("ValidateCustomCommand(%q) expected error, got nil", c.command)
+		}
+		if !c.wantErr && err != nil {
+			t.Errorf("ValidateCustomCommand(%q) unexpected error: %v", c.command, err)
+		}
+	}
+}
+
+func TestManagerOperateOverridePrecedence(t *testing.T) {
+	m := NewManager(map[string]map[string]string{
+		"myapp": {"restart": "/opt/myapp/restart.sh"},
+	})
+	if _, ok := m.customCommand("myapp", "restart"); !ok {
+		t.Fatal("expected custom command override for myapp/restart")
+	}
+	if _, ok := m.customCommand("myapp", "stop"); ok {
+		t.Fatal("did not expect override for myapp/stop")
+	}
+	if _, ok := m.customCommand("otherapp", "restart"); ok {
+		t.Fatal("did not expect override for otherapp/restart")
+	}
+}
+
+func TestOperateWithManagerInvalidName(t *testing.T) {
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "myapp", "not-a-real-manager"); err == nil {
+		t.Fatal("expected an error for an unregistered manager name")
+	}
+}
+
+func TestOperateWithManagerDefaultsToSystemd(t *testing.T) {
+	if _, err := GetBackend(defaultBackendName); err != nil {
+		t.Fatalf("expected %q to be registered by default: %v", defaultBackendName, err)
+	}
+}
+
+func TestManagerOperateRejectsUnsafeCommand(t *testing.T) {
+	m := NewManager(map[string]map[string]string{
+		"myapp": {"restart": "restart.sh; rm -rf /"},
+	})
+	if err := m.Operate("restart", "myapp"); err == nil {
+		t.Fatal("expected unsafe custom command to be rejected")
+	}
+}
+
+func TestOperateWithArgsFallsBackToOperateWithManagerWhenNoArgs(t *testing.T) {
+	m := NewManager(nil)
+	if err := m.OperateWithArgs("restart", "myapp", nil, "not-a-real-manager"); err == nil {
+		t.Fatal("expected the same error as OperateWithManager for an unregistered manager")
+	}
+}
+
+func TestOperateWithArgsRejectsBackendWithoutArgsSupport(t *testing.T) {
+	RegisterBackend(fakeMetricsBackend{name: "args-test-no-support"})
+	defer delete(registry, "args-test-no-support")
+
+	m := NewManager(nil)
+	if err := m.OperateWithArgs("restart", "myapp", []string{"--no-block"}, "args-test-no-support"); err == nil {
+		t.Fatal("expected an error when the backend doesn't implement ArgsBackend")
+	}
+}
+
+func TestSystemdBackendImplementsArgsBackend(t *testing.T) {
+	backend, err := GetBackend(defaultBackendName)
+	if err != nil {
+		t.Fatalf("expected %q to be registered: %v", defaultBackendName, err)
+	}
+	if _, ok := backend.(ArgsBackend); !ok {
+		t.Fatalf("expected the %q backend to implement ArgsBackend", defaultBackendName)
+	}
+}
+
+func withReinitializeManagerLookup(t *testing.T, name string, available bool) {
+	t.Helper()
+	orig := reinitializeManagerLookup
+	reinitializeManagerLookup = func() (string, bool) { return name, available }
+	t.Cleanup(func() { reinitializeManagerLookup = orig })
+
+	origLog := logManagerSwitch
+	logManagerSwitch = func(string, string) {}
+	t.Cleanup(func() { logManagerSwitch = origLog })
+}
+
+func TestIsCommandNotFoundErrRecognizesExecAndShellForms(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New(`exec: "systemctl": executable file not found in $PATH`), true},
+		{errors.New("bash: rc-service: command not found"), true},
+		{errors.New("Unit myapp.service could not be found"), false},
+	}
+	for _, c := range cases {
+		if got := isCommandNotFoundErr(c.err); got != c.want {
+			t.Errorf("isCommandNotFoundErr(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestOperateWithManagerRetriesOnceAfterCommandNotFound(t *testing.T) {
+	RegisterBackend(fakeMetricsBackend{name: "stale-manager", err: errors.New(`exec: "systemctl": executable file not found in $PATH`)})
+	RegisterBackend(fakeMetricsBackend{name: "fresh-manager"})
+	defer delete(registry, "stale-manager")
+	defer delete(registry, "fresh-manager")
+	withReinitializeManagerLookup(t, "fresh-manager", true)
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "myapp", "stale-manager"); err != nil {
+		t.Fatalf("expected the retry against the re-detected manager to succeed, got %v", err)
+	}
+}
+
+func TestOperateWithManagerDoesNotRetryWhenRedetectionFindsNothing(t *testing.T) {
+	wantErr := errors.New(`exec: "systemctl": executable file not found in $PATH`)
+	RegisterBackend(fakeMetricsBackend{name: "stale-manager-2", err: wantErr})
+	defer delete(registry, "stale-manager-2")
+	withReinitializeManagerLookup(t, "", false)
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "myapp", "stale-manager-2"); err != wantErr {
+		t.Fatalf("expected the original error to surface when no other manager is available, got %v", err)
+	}
+}
+
+func TestOperateWithManagerDoesNotRetryOnUnrelatedError(t *testing.T) {
+	wantErr := errors.New("Unit myapp.service could not be found")
+	RegisterBackend(fakeMetricsBackend{name: "stale-manager-3", err: wantErr})
+	RegisterBackend(fakeMetricsBackend{name: "fresh-manager-3"})
+	defer delete(registry, "stale-manager-3")
+	defer delete(registry, "fresh-manager-3")
+	withReinitializeManagerLookup(t, "fresh-manager-3", true)
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "myapp", "stale-manager-3"); err != wantErr {
+		t.Fatalf("expected the unrelated error to surface unchanged, got %v", err)
+	}
+}
+
+func TestNormalizeServiceName(t *testing.T) {
+	cases := []struct {
+		managerName, action, in, want string
+	}{
+		{"systemd", "status", "sshd", "sshd.service"},
+		{"systemd", "enable", "sshd", "sshd.service"},
+		{"systemd", "disable", "sshd.service", "sshd.service"},
+		{"openrc", "status", "sshd.service", "sshd"},
+		{"openrc", "enable", "sshd.service", "sshd"},
+		{"openrc", "disable", "sshd", "sshd"},
+		{"sysvinit", "status", "sshd.service", "sshd"},
+		{"sysvinit", "restart", "sshd", "sshd"},
+	}
+	for _, c := range cases {
+		if got := normalizeServiceName(c.managerName, c.action, c.in); got != c.want {
+			t.Errorf("normalizeServiceName(%q, %q, %q) = %q, want %q", c.managerName, c.action, c.in, got, c.want)
+		}
+	}
+}
+
+// capturingBackend records the serviceName it was actually invoked with, so
+// tests can assert Manager normalizes it before dispatch.
+type capturingBackend struct {
+	name     string
+	received *string
+}
+
+func (b capturingBackend) Name() string { return b.name }
+func (b capturingBackend) Operate(action, serviceName string) error {
+	*b.received = serviceName
+	return nil
+}
+
+func TestOperateWithManagerKeepsBareNameForNonSystemdManager(t *testing.T) {
+	var received string
+	RegisterBackend(capturingBackend{name: "normalize-test-systemd", received: &received})
+	defer delete(registry, "normalize-test-systemd")
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "sshd", "normalize-test-systemd"); err != nil {
+		t.Fatal(err)
+	}
+	if received != "sshd" {
+		t.Fatalf("expected the bare name to pass through unchanged for a non-systemd manager name, got %q", received)
+	}
+}
+
+func TestOperateWithManagerStripsSuffixForOpenrc(t *testing.T) {
+	var received string
+	RegisterBackend(capturingBackend{name: "openrc", received: &received})
+	defer RegisterBackend(openrcBackend{})
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("enable", "sshd.service", "openrc"); err != nil {
+		t.Fatal(err)
+	}
+	if received != "sshd" {
+		t.Fatalf("expected the .service suffix to be stripped for openrc, got %q", received)
+	}
+}
+
+// sequencedBackend records every action it's invoked with (in order) and
+// fails on any action listed in failOn.
+type sequencedBackend struct {
+	name    string
+	invoked *[]string
+	failOn  map[string]bool
+}
+
+func (b sequencedBackend) Name() string { return b.name }
+
+func (b sequencedBackend) Operate(action, serviceName string) error {
+	*b.invoked = append(*b.invoked, action)
+	if b.failOn[action] {
+		return errors.New(action + " failed")
+	}
+	return nil
+}
+
+func TestCustomActionsRunsEachActionInSequenceAgainstTheSameBackend(t *testing.T) {
+	var invoked []string
+	RegisterBackend(sequencedBackend{name: "custom-actions-ok", invoked: &invoked})
+	defer delete(registry, "custom-actions-ok")
+
+	m := NewManager(nil)
+	results, err := m.CustomActions("myapp", []string{"restart", "enable"}, "custom-actions-ok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Action != "restart" || results[1].Action != "enable" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[0].Error != "" || results[1].Error != "" {
+		t.Fatalf("expected no errors, got %+v", results)
+	}
+	if !reflect.DeepEqual(invoked, []string{"restart", "enable"}) {
+		t.Fatalf("expected both actions to run in order, got %v", invoked)
+	}
+}
+
+func TestCustomActionsStopsAtFirstFailureAndReturnsPartialResults(t *testing.T) {
+	var invoked []string
+	RegisterBackend(sequencedBackend{name: "custom-actions-fail", invoked: &invoked, failOn: map[string]bool{"enable": true}})
+	defer delete(registry, "custom-actions-fail")
+
+	m := NewManager(nil)
+	results, err := m.CustomActions("myapp", []string{"restart", "enable", "status"}, "custom-actions-fail")
+	if err == nil {
+		t.Fatal("expected the enable failure to surface")
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected exactly 2 results (stopping after the failure), got %+v", results)
+	}
+	if results[0].Action != "restart" || results[0].Error != "" {
+		t.Fatalf("expected restart to have succeeded, got %+v", results[0])
+	}
+	if results[1].Action != "enable" || results[1].Error == "" {
+		t.Fatalf("expected enable to have failed, got %+v", results[1])
+	}
+	if !reflect.DeepEqual(invoked, []string{"restart", "enable"}) {
+		t.Fatalf("expected status to never run after enable failed, got %v", invoked)
+	}
+}
+
+func TestOperateWithManagerAppendsSuffixForSystemd(t *testing.T) {
+	var received string
+	RegisterBackend(capturingBackend{name: "systemd", received: &received})
+	defer RegisterBackend(systemdBackend{})
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("status", "sshd", "systemd"); err != nil {
+		t.Fatal(err)
+	}
+	if received != "sshd.service" {
+		t.Fatalf("expected the .service suffix to be appended for systemd, got %q", received)
+	}
+}
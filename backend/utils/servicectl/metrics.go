@@ -0,0 +1,59 @@
+package servicectl
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// operationMetricsEnabled gates operation-outcome counting behind a single
+// atomic load, so a router with metrics scraping disabled pays nothing
+// beyond that load on every Operate/OperateWithManager call.
+var operationMetricsEnabled atomic.Bool
+
+// EnableMetrics turns on operation-outcome counting for Manager.Operate and
+// Manager.OperateWithManager.
+func EnableMetrics() { operationMetricsEnabled.Store(true) }
+
+// DisableMetrics turns off operation-outcome counting and leaves any
+// already-accumulated counters in place.
+func DisableMetrics() { operationMetricsEnabled.Store(false) }
+
+// operationKey identifies one counter: a backend, the action run against it
+// (start/stop/restart/enable/...), and whether it succeeded.
+type operationKey struct {
+	manager string
+	action  string
+	outcome string
+}
+
+var (
+	operationCountersMu sync.Mutex
+	operationCounters   = map[operationKey]uint64{}
+)
+
+// recordOperation increments the counter for one Operate/OperateWithManager
+// call. outcome is derived from err so callers don't need to know the
+// "success"/"failure" string convention.
+func recordOperation(manager, action string, err error) {
+	if !operationMetricsEnabled.Load() {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	key := operationKey{manager: manager, action: action, outcome: outcome}
+	operationCountersMu.Lock()
+	operationCounters[key]++
+	operationCountersMu.Unlock()
+}
+
+// OperationCount returns how many times action has been run against manager
+// with the given outcome ("success" or "failure") since metrics were
+// enabled. Intended for tests and for exposing alongside systemctl.Metrics()
+// on a metrics endpoint.
+func OperationCount(manager, action, outcome string) uint64 {
+	operationCountersMu.Lock()
+	defer operationCountersMu.Unlock()
+	return operationCounters[operationKey{manager: manager, action: action, outcome: outcome}]
+}
@@ -0,0 +1,57 @@
+package servicectl
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeMetricsBackend struct {
+	name string
+	err  error
+}
+
+func (b fakeMetricsBackend) Name() string                             { return b.name }
+func (b fakeMetricsBackend) Operate(action, serviceName string) error { return b.err }
+
+func TestOperateWithManagerCountsSuccessAndFailure(t *testing.T) {
+	EnableMetrics()
+	defer DisableMetrics()
+	operationCountersMu.Lock()
+	operationCounters = map[operationKey]uint64{}
+	operationCountersMu.Unlock()
+
+	RegisterBackend(fakeMetricsBackend{name: "metrics-test-ok"})
+	RegisterBackend(fakeMetricsBackend{name: "metrics-test-fail", err: errors.New("boom")})
+	defer delete(registry, "metrics-test-ok")
+	defer delete(registry, "metrics-test-fail")
+
+	m := NewManager(nil)
+	if err := m.OperateWithManager("restart", "app", "metrics-test-ok"); err != nil {
+		t.Fatal(err)
+	}
+	_ = m.OperateWithManager("restart", "app", "metrics-test-fail")
+
+	if got := OperationCount("metrics-test-ok", "restart", "success"); got != 1 {
+		t.Fatalf("expected 1 success, got %d", got)
+	}
+	if got := OperationCount("metrics-test-fail", "restart", "failure"); got != 1 {
+		t.Fatalf("expected 1 failure, got %d", got)
+	}
+}
+
+func TestOperateWithManagerDoesNotCountWhenDisabled(t *testing.T) {
+	DisableMetrics()
+	operationCountersMu.Lock()
+	operationCounters = map[operationKey]uint64{}
+	operationCountersMu.Unlock()
+
+	RegisterBackend(fakeMetricsBackend{name: "metrics-test-disabled"})
+	defer delete(registry, "metrics-test-disabled")
+
+	m := NewManager(nil)
+	_ = m.OperateWithManager("restart", "app", "metrics-test-disabled")
+
+	if got := OperationCount("metrics-test-disabled", "restart", "success"); got != 0 {
+		t.Fatalf("expected no counting while disabled, got %d", got)
+	}
+}
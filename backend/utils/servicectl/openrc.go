@@ -0,0 +1,127 @@
+package servicectl
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+// openrcInitDir holds OpenRC's init scripts, the same layout sysvinit uses.
+// It's a var, not a const, so tests can point it at a temp directory instead
+// of the real /etc/init.d.
+var openrcInitDir = "/etc/init.d"
+
+// openrcServiceExistsFn runs `rc-service -e <name>`, which OpenRC exits zero
+// for only when the service is registered, even if its script can't be
+// read directly (e.g. it's provided by a virtual/mapped service). It's a
+// var so tests can stub it without a real rc-service binary.
+var openrcServiceExistsFn = func(serviceName string) (string, error) {
+	return cmd.Execf("rc-service -e %s", serviceName)
+}
+
+// openrcBackend runs actions through rc-service, for hosts using OpenRC
+// instead of systemd or sysvinit.
+type openrcBackend struct{}
+
+func (openrcBackend) Name() string { return "openrc" }
+
+// openrcDefaultRunlevel is the runlevel "enable"/"disable" add/remove
+// serviceName from. OpenRC's "default" runlevel is the one started on a
+// normal multi-user boot, the OpenRC equivalent of systemd's "enable" making
+// a unit start on boot. It's a var, not a const, so a deployment that runs
+// services out of a non-default runlevel can override it.
+var openrcDefaultRunlevel = "default"
+
+// enable/disable aren't rc-service actions at all -- OpenRC toggles
+// boot-time startup with rc-update, not rc-service -- so they're special-
+// cased here instead of being passed straight through like every other
+// action.
+func (openrcBackend) Operate(action, serviceName string) error {
+	_, err := cmd.Execf(openrcCommand(action, serviceName))
+	return err
+}
+
+// openrcCommand builds the shell command openrcBackend.Operate runs for
+// action against serviceName: rc-update add/del for enable/disable (the
+// only way OpenRC toggles boot-time startup), rc-service for everything
+// else.
+func openrcCommand(action, serviceName string) string {
+	switch action {
+	case "enable":
+		return fmt.Sprintf("rc-update add %s %s", serviceName, openrcDefaultRunlevel)
+	case "disable":
+		return fmt.Sprintf("rc-update del %s %s", serviceName, openrcDefaultRunlevel)
+	default:
+		return fmt.Sprintf("rc-service %s %s", serviceName, action)
+	}
+}
+
+func init() {
+	RegisterBackend(openrcBackend{})
+}
+
+// openrcActivePattern matches the "active" status line `rc-service <name>
+// status` prints, tolerating the two forms real-world openrc scripts use: a
+// bulleted "* status: started" (the colorized prompt style) and a plain
+// "service is running" emitted by some service scripts instead of the
+// generic rc-service wrapper text. An earlier version of this match was
+// anchored to "status: started" with no bullet allowance, so it reported a
+// false "stopped" for both of these.
+var openrcActivePattern = regexp.MustCompile(`(?i)\*?\s*status:\s*started|is running`)
+
+// ParseOpenrcStatus reports whether output (the combined output of
+// `rc-service <name> status`) indicates the service is active.
+func ParseOpenrcStatus(output string) bool {
+	return openrcActivePattern.MatchString(output)
+}
+
+// rcStatusActivePattern builds a regex matching serviceName's entry in
+// `rc-status --all` output when it's started, e.g. the line
+// " sshd                     [  started  ]".
+func rcStatusActivePattern(serviceName string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(?m)^\s*%s\s*\[\s*started\s*\]`, regexp.QuoteMeta(serviceName)))
+}
+
+// IsActiveInRCStatus reports whether serviceName appears as started in the
+// combined output of `rc-status --all`.
+func IsActiveInRCStatus(rcStatusAllOutput, serviceName string) bool {
+	return rcStatusActivePattern(serviceName).MatchString(rcStatusAllOutput)
+}
+
+// statusProbeEnv forces the C locale on status probes so their output stays
+// in English regardless of the host's configured locale -- ParseOpenrcStatus
+// and IsActiveInRCStatus only recognize the English "started"/"running"
+// wording, which a localized rc-service/rc-status would otherwise replace.
+var statusProbeEnv = []string{"LANG=C", "LC_ALL=C"}
+
+// IsOpenrcActive reports whether serviceName is active under OpenRC. It
+// first runs `rc-service <name> status` directly -- a zero exit code or
+// output matching ParseOpenrcStatus both mean active -- and only falls back
+// to `rc-status --all` when neither signal from rc-service confirms it,
+// since some service scripts exit non-zero even while printing a
+// recognizable "started" status.
+func IsOpenrcActive(serviceName string) (bool, error) {
+	out, err := cmd.ExecfWithEnv(statusProbeEnv, "rc-service %s status", serviceName)
+	if err == nil || ParseOpenrcStatus(out) {
+		return true, nil
+	}
+	allOut, allErr := cmd.ExecfWithEnv(statusProbeEnv, "rc-status --all")
+	if allErr == nil && IsActiveInRCStatus(allOut, serviceName) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// OpenrcServiceExists reports whether serviceName is a real OpenRC service:
+// either its script is present in openrcInitDir, or `rc-service -e` confirms
+// it's registered even without a locally readable script.
+func OpenrcServiceExists(serviceName string) bool {
+	if info, err := os.Stat(path.Join(openrcInitDir, serviceName)); err == nil && !info.IsDir() {
+		return true
+	}
+	_, err := openrcServiceExistsFn(serviceName)
+	return err == nil
+}
@@ -0,0 +1,137 @@
+package servicectl
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestParseOpenrcStatusRecognizesBulletedStarted(t *testing.T) {
+	if !ParseOpenrcStatus(" * status: started") {
+		t.Fatal("expected bulleted 'status: started' to be recognized as active")
+	}
+}
+
+func TestParseOpenrcStatusRecognizesPlainStarted(t *testing.T) {
+	if !ParseOpenrcStatus("status: started") {
+		t.Fatal("expected plain 'status: started' to be recognized as active")
+	}
+}
+
+func TestParseOpenrcStatusRecognizesIsRunning(t *testing.T) {
+	if !ParseOpenrcStatus("sshd is running") {
+		t.Fatal("expected 'is running' to be recognized as active")
+	}
+}
+
+func TestParseOpenrcStatusRejectsStopped(t *testing.T) {
+	if ParseOpenrcStatus(" * status: stopped") {
+		t.Fatal("expected 'status: stopped' to not be recognized as active")
+	}
+}
+
+func TestParseOpenrcStatusRejectsCrashed(t *testing.T) {
+	if ParseOpenrcStatus(" * status: crashed") {
+		t.Fatal("expected 'status: crashed' to not be recognized as active")
+	}
+}
+
+func TestIsActiveInRCStatusFindsStartedService(t *testing.T) {
+	output := " Runlevel: default\n sshd                     [  started  ]\n cron                     [  stopped  ]\n"
+	if !IsActiveInRCStatus(output, "sshd") {
+		t.Fatal("expected sshd to be recognized as started")
+	}
+	if IsActiveInRCStatus(output, "cron") {
+		t.Fatal("expected cron to not be recognized as started")
+	}
+}
+
+func TestIsActiveInRCStatusMissingServiceNotActive(t *testing.T) {
+	output := " sshd                     [  started  ]\n"
+	if IsActiveInRCStatus(output, "nginx") {
+		t.Fatal("expected a service absent from rc-status output to not be active")
+	}
+}
+
+func TestOpenrcCommandEnableUsesRCUpdateAdd(t *testing.T) {
+	got := openrcCommand("enable", "sshd")
+	want := "rc-update add sshd " + openrcDefaultRunlevel
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpenrcCommandDisableUsesRCUpdateDel(t *testing.T) {
+	got := openrcCommand("disable", "sshd")
+	want := "rc-update del sshd " + openrcDefaultRunlevel
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpenrcCommandEnableRespectsConfiguredRunlevel(t *testing.T) {
+	original := openrcDefaultRunlevel
+	openrcDefaultRunlevel = "boot"
+	defer func() { openrcDefaultRunlevel = original }()
+
+	if got, want := openrcCommand("enable", "sshd"), "rc-update add sshd boot"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpenrcCommandOtherActionsUseRCService(t *testing.T) {
+	got := openrcCommand("restart", "sshd")
+	want := "rc-service sshd restart"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func withFakeOpenrcServiceExists(t *testing.T, err error) {
+	t.Helper()
+	orig := openrcServiceExistsFn
+	openrcServiceExistsFn = func(string) (string, error) { return "", err }
+	t.Cleanup(func() { openrcServiceExistsFn = orig })
+}
+
+func TestOpenrcServiceExistsFindsScriptOnly(t *testing.T) {
+	initDir := path.Join(t.TempDir(), "init.d")
+	if err := os.MkdirAll(initDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(initDir, "sshd"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	origInitDir := openrcInitDir
+	openrcInitDir = initDir
+	defer func() { openrcInitDir = origInitDir }()
+	withFakeOpenrcServiceExists(t, errors.New("not found"))
+
+	if !OpenrcServiceExists("sshd") {
+		t.Fatal("expected a service with only a script present to be reported as existing")
+	}
+}
+
+func TestOpenrcServiceExistsFindsViaRCServiceWithoutScript(t *testing.T) {
+	origInitDir := openrcInitDir
+	openrcInitDir = path.Join(t.TempDir(), "init.d")
+	defer func() { openrcInitDir = origInitDir }()
+	withFakeOpenrcServiceExists(t, nil)
+
+	if !OpenrcServiceExists("sshd") {
+		t.Fatal("expected rc-service -e to confirm existence without a readable script")
+	}
+}
+
+func TestOpenrcServiceExistsFalseWhenNeitherConfirms(t *testing.T) {
+	origInitDir := openrcInitDir
+	openrcInitDir = path.Join(t.TempDir(), "init.d")
+	defer func() { openrcInitDir = origInitDir }()
+	withFakeOpenrcServiceExists(t, errors.New("not found"))
+
+	if OpenrcServiceExists("sshd") {
+		t.Fatal("expected no script and a failing rc-service -e to report as not existing")
+	}
+}
@@ -0,0 +1,89 @@
+package servicectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/1Panel-dev/1Panel/backend/constant"
+)
+
+// pinFile persists manual keyword->service overrides separately from
+// aliasFile, so a discovery cache cleanup never wipes an operator's pins.
+var pinFile = path.Join(constant.ResourceDir, "svcaliases_pinned.json")
+
+var (
+	pinCacheMu sync.RWMutex
+	pinCache   = map[string]string{}
+)
+
+// PinServiceName pins keyword to serviceName, bypassing discovery for every
+// future lookup of that keyword. exists validates that serviceName is a real
+// service on this host before the pin is accepted.
+func PinServiceName(keyword, serviceName string, exists func(string) (bool, error)) error {
+	ok, err := exists(serviceName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("service %q does not exist", serviceName)
+	}
+	pinCacheMu.Lock()
+	pinCache[keyword] = serviceName
+	data, marshalErr := json.Marshal(pinCache)
+	pinCacheMu.Unlock()
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return writePinFile(data)
+}
+
+// UnpinServiceName removes a pin, letting keyword fall back to discovery.
+func UnpinServiceName(keyword string) error {
+	pinCacheMu.Lock()
+	delete(pinCache, keyword)
+	data, err := json.Marshal(pinCache)
+	pinCacheMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writePinFile(data)
+}
+
+// LoadPins reads previously saved pins from pinFile into memory. A missing
+// file just means nothing has been pinned yet.
+func LoadPins() error {
+	data, err := os.ReadFile(pinFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	loaded := map[string]string{}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return err
+	}
+	pinCacheMu.Lock()
+	pinCache = loaded
+	pinCacheMu.Unlock()
+	return nil
+}
+
+func writePinFile(data []byte) error {
+	tmp := pinFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, pinFile)
+}
+
+// lookupPin returns the pinned service name for keyword, if any.
+func lookupPin(keyword string) (string, bool) {
+	pinCacheMu.RLock()
+	defer pinCacheMu.RUnlock()
+	name, ok := pinCache[keyword]
+	return name, ok
+}
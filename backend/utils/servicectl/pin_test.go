@@ -0,0 +1,52 @@
+package servicectl
+
+import (
+	"path"
+	"testing"
+)
+
+func TestPinServiceNameTakesPrecedenceOverDiscovery(t *testing.T) {
+	origPinFile := pinFile
+	pinFile = path.Join(t.TempDir(), "svcaliases_pinned.json")
+	defer func() { pinFile = origPinFile }()
+
+	pinCacheMu.Lock()
+	pinCache = map[string]string{}
+	pinCacheMu.Unlock()
+	aliasCacheMu.Lock()
+	aliasCache = map[string]string{"ssh": "ssh.service"}
+	aliasCacheMu.Unlock()
+
+	exists := func(string) (bool, error) { return true, nil }
+	if err := PinServiceName("ssh", "openssh-server.service", exists); err != nil {
+		t.Fatal(err)
+	}
+
+	resolve := func(keyword string) (string, error) {
+		t.Fatal("discovery should not run once a pin exists")
+		return "", nil
+	}
+	name, err := ResolveServiceName("ssh", resolve)
+	if err != nil || name != "openssh-server.service" {
+		t.Fatalf("expected pinned name, got %q, %v", name, err)
+	}
+
+	if err := UnpinServiceName("ssh"); err != nil {
+		t.Fatal(err)
+	}
+	name, err = ResolveServiceName("ssh", func(string) (string, error) { return "ssh.service", nil })
+	if err != nil || name != "ssh.service" {
+		t.Fatalf("expected discovery to resume after unpin, got %q, %v", name, err)
+	}
+}
+
+func TestPinServiceNameRejectsMissingService(t *testing.T) {
+	origPinFile := pinFile
+	pinFile = path.Join(t.TempDir(), "svcaliases_pinned.json")
+	defer func() { pinFile = origPinFile }()
+
+	exists := func(string) (bool, error) { return false, nil }
+	if err := PinServiceName("ssh", "bogus.service", exists); err == nil {
+		t.Fatal("expected an error pinning a nonexistent service")
+	}
+}
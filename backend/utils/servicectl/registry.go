@@ -0,0 +1,36 @@
+package servicectl
+
+import "fmt"
+
+// Backend is implemented by each supported init system's command backend
+// (systemd, openrc and sysvinit each register one from their own init()).
+type Backend interface {
+	Name() string
+	Operate(action, serviceName string) error
+}
+
+// ArgsBackend is implemented by backends that can pass extra CLI flags
+// through to an action (e.g. systemd's "--no-block"), on top of the base
+// Backend.Operate every registered manager already supports.
+type ArgsBackend interface {
+	Backend
+	OperateWithArgs(action, serviceName string, args []string) error
+}
+
+var registry = map[string]Backend{}
+
+// RegisterBackend makes a Backend available for lookup by name, so an
+// operation can request it explicitly via Manager.OperateWithManager.
+func RegisterBackend(b Backend) {
+	registry[b.Name()] = b
+}
+
+// GetBackend looks up a registered Backend by name, failing with a
+// descriptive error if it isn't available on this host.
+func GetBackend(name string) (Backend, error) {
+	b, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("service manager %q is not registered", name)
+	}
+	return b, nil
+}
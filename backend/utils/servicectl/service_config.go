@@ -0,0 +1,43 @@
+package servicectl
+
+// ServiceConfig holds the resolved service name for keyword under every
+// registered Backend, instead of just the one that happened to be active
+// when it was built. A Manager that only remembers a single backend's name
+// goes stale the moment the registry changes (e.g. a host gains an openrc
+// backend at runtime); looking the name up fresh per backend here means a
+// long-lived caller keeps working across that kind of change.
+type ServiceConfig struct {
+	Keyword     string
+	ServiceName map[string]string
+}
+
+// NewServiceConfig resolves keyword against every registered backend. Each
+// backend can have its own naming convention (systemd units end in
+// ".service", openrc/sysvinit scripts don't, etc.), so normalizeName maps a
+// backend name to the function that turns keyword into that backend's
+// service name. A backend missing from normalizeName just uses keyword
+// unchanged.
+func NewServiceConfig(keyword string, normalizeName map[string]func(string) string) *ServiceConfig {
+	config := &ServiceConfig{
+		Keyword:     keyword,
+		ServiceName: make(map[string]string, len(registry)),
+	}
+	for name := range registry {
+		normalize, ok := normalizeName[name]
+		if !ok {
+			normalize = func(k string) string { return k }
+		}
+		config.ServiceName[name] = normalize(keyword)
+	}
+	return config
+}
+
+// NameFor returns the service name stored for managerName, falling back to
+// the original keyword if that backend wasn't registered when the
+// ServiceConfig was built (e.g. it registers itself later at runtime).
+func (c *ServiceConfig) NameFor(managerName string) string {
+	if name, ok := c.ServiceName[managerName]; ok {
+		return name
+	}
+	return c.Keyword
+}
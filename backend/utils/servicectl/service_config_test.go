@@ -0,0 +1,36 @@
+package servicectl
+
+import "testing"
+
+func TestNewServiceConfigFillsNameForEveryRegisteredBackend(t *testing.T) {
+	config := NewServiceConfig("nginx", map[string]func(string) string{
+		"systemd": func(k string) string { return k + ".service" },
+	})
+
+	if config.NameFor("systemd") != "nginx.service" {
+		t.Fatalf("expected systemd name to use normalizeName, got %q", config.NameFor("systemd"))
+	}
+	if config.NameFor("sysvinit") != "nginx" {
+		t.Fatalf("expected sysvinit name to fall back to the keyword unchanged, got %q", config.NameFor("sysvinit"))
+	}
+}
+
+func TestServiceConfigSurvivesBackendRegisteredAfterBuild(t *testing.T) {
+	config := NewServiceConfig("nginx", nil)
+
+	// A backend that registers itself after the ServiceConfig was built
+	// (e.g. a manager re-detection) wasn't in the registry snapshot, so
+	// it has no stored name, but NameFor must still return something
+	// usable instead of an empty string.
+	RegisterBackend(fakeBackendForServiceConfigTest{})
+	defer delete(registry, "fake-for-service-config-test")
+
+	if config.NameFor("fake-for-service-config-test") != "nginx" {
+		t.Fatalf("expected fallback to keyword for a backend unknown at build time, got %q", config.NameFor("fake-for-service-config-test"))
+	}
+}
+
+type fakeBackendForServiceConfigTest struct{}
+
+func (fakeBackendForServiceConfigTest) Name() string                             { return "fake-for-service-config-test" }
+func (fakeBackendForServiceConfigTest) Operate(action, serviceName string) error { return nil }
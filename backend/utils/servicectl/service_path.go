@@ -0,0 +1,115 @@
+package servicectl
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// systemdUnitDirs are the standard locations systemd loads unit files from,
+// searched in the order systemd itself applies overrides: /etc first (local
+// admin overrides), then /run (runtime-generated units), then the
+// distro-shipped locations last.
+var systemdUnitDirs = []string{
+	"/etc/systemd/system",
+	"/run/systemd/system",
+	"/usr/lib/systemd/system",
+	"/lib/systemd/system",
+}
+
+// ServicePath is what GetServicePath found for a unit name: the unit file
+// itself, and its drop-in override directory if one exists.
+type ServicePath struct {
+	UnitPath string
+	// DropInDir is "<unit>.d", a directory of .conf snippets systemd merges
+	// over the unit file at load time, empty if none exists.
+	DropInDir string
+}
+
+// GetServicePath searches systemdUnitDirs for serviceName's unit file (e.g.
+// "supervisord.service"), so a caller doesn't have to guess which of the
+// several standard locations a given host installed it into. When
+// serviceName itself isn't found but it looks like a template instance
+// (e.g. "foo@bar.service"), it also tries the template unit ("foo@.service"),
+// since that's the file that actually exists on disk for every instance.
+func GetServicePath(serviceName string) (ServicePath, bool) {
+	return getServicePath(serviceName, fileExists, dirExists)
+}
+
+func getServicePath(serviceName string, fileExists, dirExists func(string) bool) (ServicePath, bool) {
+	unitPath, ok := findUnitFile(serviceName, fileExists)
+	if !ok {
+		return ServicePath{}, false
+	}
+	result := ServicePath{UnitPath: unitPath}
+	if dir, ok := findDropInDir(serviceName, dirExists); ok {
+		result.DropInDir = dir
+	}
+	return result, true
+}
+
+func findUnitFile(serviceName string, exists func(string) bool) (string, bool) {
+	for _, dir := range systemdUnitDirs {
+		p := path.Join(dir, serviceName)
+		if exists(p) {
+			return p, true
+		}
+	}
+	if template, ok := templateUnitName(serviceName); ok {
+		for _, dir := range systemdUnitDirs {
+			p := path.Join(dir, template)
+			if exists(p) {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+func findDropInDir(serviceName string, dirExists func(string) bool) (string, bool) {
+	for _, dir := range systemdUnitDirs {
+		p := path.Join(dir, serviceName+".d")
+		if dirExists(p) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// templateUnitName reduces a systemd instance unit name ("foo@bar.service")
+// to its template unit name ("foo@.service"), the file that's actually on
+// disk for every instance of that template. The second return is false for
+// a plain (non-template) unit name.
+func templateUnitName(serviceName string) (string, bool) {
+	at := strings.Index(serviceName, "@")
+	if at == -1 || at == len(serviceName)-1 {
+		return "", false
+	}
+	ext := path.Ext(serviceName)
+	return serviceName[:at+1] + ext, true
+}
+
+// GetInitScriptPath searches the OpenRC/sysvinit init-script directories for
+// serviceName's script, for hosts with no systemd unit for GetServicePath to
+// find -- there the service definition is a shell script, not an ini-style
+// unit, so callers that need to inspect its invocation (e.g. to recover a
+// `-c <path>` flag) have to read the script itself.
+func GetInitScriptPath(serviceName string) (string, bool) {
+	for _, dir := range []string{openrcInitDir, sysvinitInitDir} {
+		p := path.Join(dir, serviceName)
+		if fileExists(p) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func fileExists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
+
+func dirExists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,86 @@
+package servicectl
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestTemplateUnitNameReducesInstanceToTemplate(t *testing.T) {
+	template, ok := templateUnitName("clamd@scan.service")
+	if !ok || template != "clamd@.service" {
+		t.Fatalf("expected clamd@.service, got %q, ok=%v", template, ok)
+	}
+}
+
+func TestTemplateUnitNameRejectsPlainUnit(t *testing.T) {
+	if _, ok := templateUnitName("nginx.service"); ok {
+		t.Fatal("expected a plain unit name to not be treated as an instance")
+	}
+}
+
+func TestGetServicePathFindsPlainUnit(t *testing.T) {
+	exists := func(p string) bool { return p == "/etc/systemd/system/nginx.service" }
+	noDirs := func(string) bool { return false }
+
+	result, ok := getServicePath("nginx.service", exists, noDirs)
+	if !ok || result.UnitPath != "/etc/systemd/system/nginx.service" {
+		t.Fatalf("unexpected result: %+v, ok=%v", result, ok)
+	}
+}
+
+func TestGetServicePathFallsBackToTemplateUnit(t *testing.T) {
+	exists := func(p string) bool { return p == "/usr/lib/systemd/system/clamd@.service" }
+	noDirs := func(string) bool { return false }
+
+	result, ok := getServicePath("clamd@scan.service", exists, noDirs)
+	if !ok || result.UnitPath != "/usr/lib/systemd/system/clamd@.service" {
+		t.Fatalf("unexpected result: %+v, ok=%v", result, ok)
+	}
+}
+
+func TestGetServicePathReportsDropInDir(t *testing.T) {
+	exists := func(p string) bool { return p == "/usr/lib/systemd/system/nginx.service" }
+	isDir := func(p string) bool { return p == "/etc/systemd/system/nginx.service.d" }
+
+	result, ok := getServicePath("nginx.service", exists, isDir)
+	if !ok || result.DropInDir != "/etc/systemd/system/nginx.service.d" {
+		t.Fatalf("unexpected result: %+v, ok=%v", result, ok)
+	}
+}
+
+func TestGetServicePathMissingUnit(t *testing.T) {
+	none := func(string) bool { return false }
+	if _, ok := getServicePath("bogus.service", none, none); ok {
+		t.Fatal("expected no match for a unit that doesn't exist anywhere")
+	}
+}
+
+func TestGetInitScriptPathFindsOpenrcScript(t *testing.T) {
+	dir := path.Join(t.TempDir(), "init.d")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, "supervisord"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	origOpenrc, origSysvinit := openrcInitDir, sysvinitInitDir
+	openrcInitDir, sysvinitInitDir = dir, path.Join(t.TempDir(), "missing")
+	defer func() { openrcInitDir, sysvinitInitDir = origOpenrc, origSysvinit }()
+
+	scriptPath, ok := GetInitScriptPath("supervisord")
+	if !ok || scriptPath != path.Join(dir, "supervisord") {
+		t.Fatalf("unexpected result: %q, ok=%v", scriptPath, ok)
+	}
+}
+
+func TestGetInitScriptPathMissingScript(t *testing.T) {
+	origOpenrc, origSysvinit := openrcInitDir, sysvinitInitDir
+	openrcInitDir = path.Join(t.TempDir(), "missing1")
+	sysvinitInitDir = path.Join(t.TempDir(), "missing2")
+	defer func() { openrcInitDir, sysvinitInitDir = origOpenrc, origSysvinit }()
+
+	if _, ok := GetInitScriptPath("supervisord"); ok {
+		t.Fatal("expected no match when neither init dir has the script")
+	}
+}
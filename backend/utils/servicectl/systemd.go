@@ -0,0 +1,23 @@
+package servicectl
+
+import "github.com/1Panel-dev/1Panel/backend/utils/systemctl"
+
+// systemdBackend is the default Backend, used when no operation explicitly
+// selects a different registered manager.
+type systemdBackend struct{}
+
+func (systemdBackend) Name() string { return "systemd" }
+
+func (systemdBackend) Operate(action, serviceName string) error {
+	return systemctl.Operate(action, serviceName)
+}
+
+// OperateWithArgs runs action against serviceName with extra systemctl
+// flags, e.g. "restart" with "--no-block".
+func (systemdBackend) OperateWithArgs(action, serviceName string, args []string) error {
+	return systemctl.CustomActionArgs(action, args, serviceName)
+}
+
+func init() {
+	RegisterBackend(systemdBackend{})
+}
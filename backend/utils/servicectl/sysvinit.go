@@ -0,0 +1,124 @@
+package servicectl
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+// sysvinitRcDirs are the standard runlevel directories update-rc.d
+// populates with S<NN><service> start-links for services enabled at boot.
+var sysvinitRcDirs = []string{"/etc/rc2.d", "/etc/rc3.d", "/etc/rc4.d", "/etc/rc5.d"}
+
+// sysvinitInitDir holds the init scripts themselves. It's a var, not a
+// const, so tests can point it at a temp directory instead of the real
+// /etc/init.d.
+var sysvinitInitDir = "/etc/init.d"
+
+// sysvinitRestartFallbackEnabled opts into falling back to stop+start when a
+// sysvinit script's restart action fails, since many legacy/custom scripts
+// never implement restart and just exit non-zero, leaving the service
+// stopped or in whatever state it was already in. Off by default: for a
+// script that does support restart, the fallback never runs, but for one
+// that doesn't, silently swallowing the failure and taking it down a
+// different path than the caller asked for is surprising unless they've
+// opted in.
+var sysvinitRestartFallbackEnabled = false
+
+// sysvinitBackend runs actions through the classic `service` wrapper, for
+// hosts without systemd.
+type sysvinitBackend struct{}
+
+func (sysvinitBackend) Name() string { return "sysvinit" }
+
+func (sysvinitBackend) Operate(action, serviceName string) error {
+	if action == "restart" && sysvinitRestartFallbackEnabled {
+		return sysvinitRestartWithFallback(serviceName)
+	}
+	_, err := cmd.Execf("service %s %s", serviceName, action)
+	return err
+}
+
+// sysvinitRestartWithFallback tries restart first; if the script doesn't
+// support it and returns an error, it falls back to stop then start and
+// verifies the service actually ends up active, so a script that silently
+// no-ops on stop/start doesn't look like a successful restart.
+func sysvinitRestartWithFallback(serviceName string) error {
+	if _, err := cmd.Execf("service %s restart", serviceName); err == nil {
+		return nil
+	}
+	if _, err := cmd.Execf("service %s stop", serviceName); err != nil {
+		return err
+	}
+	if _, err := cmd.Execf("service %s start", serviceName); err != nil {
+		return err
+	}
+	active, err := sysvinitIsActive(serviceName)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return fmt.Errorf("service %s did not become active after falling back to stop+start", serviceName)
+	}
+	return nil
+}
+
+// sysvinitIsActive reports whether serviceName's `service status` exits
+// successfully, which is the closest thing sysvinit scripts have to a
+// standardized active check.
+func sysvinitIsActive(serviceName string) (bool, error) {
+	_, err := cmd.Execf("service %s status", serviceName)
+	return err == nil, nil
+}
+
+func init() {
+	RegisterBackend(sysvinitBackend{})
+}
+
+// enabledLinkPattern matches an rc*.d start-link name exactly, e.g.
+// "S20ssh", so a service named "ssh" can never match a link belonging to
+// "sshd" the way the old "S*ssh" glob did.
+func enabledLinkPattern(serviceName string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^S\d{2}%s$`, regexp.QuoteMeta(serviceName)))
+}
+
+// isEnabledLink reports whether entryName is an rc*.d start-link for
+// exactly serviceName, not merely one containing it as a substring.
+func isEnabledLink(entryName, serviceName string) bool {
+	return enabledLinkPattern(serviceName).MatchString(entryName)
+}
+
+// IsSysvinitEnabled reports whether serviceName is enabled to start at boot
+// under sysvinit. It looks for an exact "S<NN><serviceName>" start-link in
+// each standard runlevel directory instead of glob-matching "S*<name>",
+// which produced false positives for names that are substrings of another
+// service's link (e.g. "ssh" matching "sshd").
+func IsSysvinitEnabled(serviceName string) (bool, error) {
+	for _, dir := range sysvinitRcDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if isEnabledLink(entry.Name(), serviceName) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// SysvinitServiceExists reports whether serviceName is a real sysvinit
+// service: either its script is present in sysvinitInitDir, or it has an
+// rc*.d start-link without a readable script, which busybox's stripped-down
+// init systems sometimes leave as the only evidence a service exists.
+func SysvinitServiceExists(serviceName string) bool {
+	if info, err := os.Stat(path.Join(sysvinitInitDir, serviceName)); err == nil && !info.IsDir() {
+		return true
+	}
+	enabled, _ := IsSysvinitEnabled(serviceName)
+	return enabled
+}
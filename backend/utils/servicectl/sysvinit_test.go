@@ -0,0 +1,189 @@
+package servicectl
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+// withFakeServiceScript puts a shell script named "service" ahead of the
+// real one on PATH, so sysvinitBackend actually runs it instead of hitting
+// whatever real init system the test host has.
+func withFakeServiceScript(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	scriptPath := path.Join(dir, "service")
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	origPath := os.Getenv("PATH")
+	_ = os.Setenv("PATH", dir+string(os.PathListSeparator)+origPath)
+	t.Cleanup(func() { _ = os.Setenv("PATH", origPath) })
+}
+
+func withSysvinitRestartFallback(t *testing.T, enabled bool) {
+	t.Helper()
+	orig := sysvinitRestartFallbackEnabled
+	sysvinitRestartFallbackEnabled = enabled
+	t.Cleanup(func() { sysvinitRestartFallbackEnabled = orig })
+}
+
+func TestSysvinitOperateRestartFailsOutrightWhenFallbackDisabled(t *testing.T) {
+	withSysvinitRestartFallback(t, false)
+	withFakeServiceScript(t, `
+case "$2" in
+  restart) exit 1 ;;
+  *) exit 0 ;;
+esac
+`)
+	if err := (sysvinitBackend{}).Operate("restart", "myapp"); err == nil {
+		t.Fatal("expected the raw restart failure to surface when the fallback is disabled")
+	}
+}
+
+func TestSysvinitOperateRestartFallsBackToStopStartWhenEnabled(t *testing.T) {
+	withSysvinitRestartFallback(t, true)
+	withFakeServiceScript(t, `
+case "$2" in
+  restart) exit 1 ;;
+  status) exit 0 ;;
+  *) exit 0 ;;
+esac
+`)
+	if err := (sysvinitBackend{}).Operate("restart", "myapp"); err != nil {
+		t.Fatalf("expected the stop+start fallback to succeed, got %v", err)
+	}
+}
+
+func TestSysvinitOperateRestartFallbackFailsWhenServiceStaysInactive(t *testing.T) {
+	withSysvinitRestartFallback(t, true)
+	withFakeServiceScript(t, `
+case "$2" in
+  restart) exit 1 ;;
+  status) exit 1 ;;
+  *) exit 0 ;;
+esac
+`)
+	if err := (sysvinitBackend{}).Operate("restart", "myapp"); err == nil {
+		t.Fatal("expected an error when the service never becomes active after the fallback")
+	}
+}
+
+func TestSysvinitOperateNonRestartActionsIgnoreFallbackFlag(t *testing.T) {
+	withSysvinitRestartFallback(t, true)
+	withFakeServiceScript(t, "exit 0")
+	if err := (sysvinitBackend{}).Operate("stop", "myapp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsEnabledLinkExactMatchOnly(t *testing.T) {
+	cases := []struct {
+		entry, service string
+		want           bool
+	}{
+		{"S20ssh", "ssh", true},
+		{"S20sshd", "ssh", false},
+		{"S05ssh", "ssh", true},
+		{"K20ssh", "ssh", false},
+		{"S20ssh", "sshd", false},
+	}
+	for _, c := range cases {
+		if got := isEnabledLink(c.entry, c.service); got != c.want {
+			t.Errorf("isEnabledLink(%q, %q) = %v, want %v", c.entry, c.service, got, c.want)
+		}
+	}
+}
+
+func TestIsSysvinitEnabledIgnoresSubstringMatches(t *testing.T) {
+	rc2 := path.Join(t.TempDir(), "rc2.d")
+	if err := os.MkdirAll(rc2, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(rc2, "S20sshd"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDirs := sysvinitRcDirs
+	sysvinitRcDirs = []string{rc2}
+	defer func() { sysvinitRcDirs = origDirs }()
+
+	enabled, err := IsSysvinitEnabled("ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled {
+		t.Fatal("expected ssh not to be reported enabled when only sshd has a start-link")
+	}
+}
+
+func TestIsSysvinitEnabledFindsExactLink(t *testing.T) {
+	rc2 := path.Join(t.TempDir(), "rc2.d")
+	if err := os.MkdirAll(rc2, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(rc2, "S20ssh"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDirs := sysvinitRcDirs
+	sysvinitRcDirs = []string{rc2}
+	defer func() { sysvinitRcDirs = origDirs }()
+
+	enabled, err := IsSysvinitEnabled("ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatal("expected ssh to be reported enabled")
+	}
+}
+
+func TestSysvinitServiceExistsFindsScriptOnly(t *testing.T) {
+	initDir := path.Join(t.TempDir(), "init.d")
+	if err := os.MkdirAll(initDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(initDir, "ssh"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	origInitDir, origRcDirs := sysvinitInitDir, sysvinitRcDirs
+	sysvinitInitDir = initDir
+	sysvinitRcDirs = []string{path.Join(t.TempDir(), "rc2.d")}
+	defer func() { sysvinitInitDir, sysvinitRcDirs = origInitDir, origRcDirs }()
+
+	if !SysvinitServiceExists("ssh") {
+		t.Fatal("expected a service with only a script present to be reported as existing")
+	}
+}
+
+func TestSysvinitServiceExistsFindsSymlinkOnly(t *testing.T) {
+	rc2 := path.Join(t.TempDir(), "rc2.d")
+	if err := os.MkdirAll(rc2, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(rc2, "S20ssh"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origInitDir, origRcDirs := sysvinitInitDir, sysvinitRcDirs
+	sysvinitInitDir = path.Join(t.TempDir(), "init.d")
+	sysvinitRcDirs = []string{rc2}
+	defer func() { sysvinitInitDir, sysvinitRcDirs = origInitDir, origRcDirs }()
+
+	if !SysvinitServiceExists("ssh") {
+		t.Fatal("expected a service with only an rc*.d start-link to be reported as existing")
+	}
+}
+
+func TestSysvinitServiceExistsFalseWhenNeitherPresent(t *testing.T) {
+	origInitDir, origRcDirs := sysvinitInitDir, sysvinitRcDirs
+	sysvinitInitDir = path.Join(t.TempDir(), "init.d")
+	sysvinitRcDirs = []string{path.Join(t.TempDir(), "rc2.d")}
+	defer func() { sysvinitInitDir, sysvinitRcDirs = origInitDir, origRcDirs }()
+
+	if SysvinitServiceExists("ssh") {
+		t.Fatal("expected no script and no start-link to report as not existing")
+	}
+}
@@ -0,0 +1,242 @@
+// Package supervisorrpc is a minimal XML-RPC client for supervisord's
+// control interface (http://supervisord.org/api.html), used to avoid
+// shelling out to supervisorctl for every process operation. It only
+// implements the handful of methods and XML-RPC value types the panel
+// actually needs, not the full XML-RPC spec.
+package supervisorrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Client talks to a single supervisord instance's XML-RPC endpoint, either
+// over the unix socket configured by [unix_http_server] or the TCP address
+// configured by [inet_http_server].
+type Client struct {
+	httpClient *http.Client
+	endpoint   string
+	username   string
+	password   string
+}
+
+// NewUnixSocketClient builds a Client that dials socketPath instead of
+// opening a TCP connection, matching a supervisord configured with only a
+// [unix_http_server] section.
+func NewUnixSocketClient(socketPath, username, password string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+					return net.Dial("unix", socketPath)
+				},
+			},
+			Timeout: 10 * time.Second,
+		},
+		endpoint: "http://unix/RPC2",
+		username: username,
+		password: password,
+	}
+}
+
+// NewInetClient builds a Client that talks to supervisord over TCP at
+// address (host:port), matching an [inet_http_server] section.
+func NewInetClient(address, username, password string) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		endpoint:   fmt.Sprintf("http://%s/RPC2", address),
+		username:   username,
+		password:   password,
+	}
+}
+
+// ProcessInfo mirrors the fields of supervisor.getAllProcessInfo's struct
+// that the panel's process list actually displays.
+type ProcessInfo struct {
+	Name        string
+	Group       string
+	StateName   string
+	PID         int
+	Description string
+}
+
+// GetAllProcessInfo calls supervisor.getAllProcessInfo, replacing the
+// positional-field parsing of `supervisorctl status` output.
+func (c *Client) GetAllProcessInfo() ([]ProcessInfo, error) {
+	value, err := c.call("supervisor.getAllProcessInfo")
+	if err != nil {
+		return nil, err
+	}
+	if value.Array == nil {
+		return nil, fmt.Errorf("unexpected getAllProcessInfo response shape")
+	}
+	infos := make([]ProcessInfo, 0, len(value.Array.Data.Values))
+	for _, v := range value.Array.Data.Values {
+		if v.Struct == nil {
+			continue
+		}
+		members := v.Struct.members()
+		infos = append(infos, ProcessInfo{
+			Name:        members["name"].stringValue(),
+			Group:       members["group"].stringValue(),
+			StateName:   members["statename"].stringValue(),
+			PID:         members["pid"].intValue(),
+			Description: members["description"].stringValue(),
+		})
+	}
+	return infos, nil
+}
+
+// StartProcess calls supervisor.startProcess for name, waiting for the
+// process to leave the STARTING state.
+func (c *Client) StartProcess(name string) error {
+	_, err := c.call("supervisor.startProcess", stringParam(name), boolParam(true))
+	return err
+}
+
+// StopProcess calls supervisor.stopProcess for name, waiting for the
+// process to leave the STOPPING state.
+func (c *Client) StopProcess(name string) error {
+	_, err := c.call("supervisor.stopProcess", stringParam(name), boolParam(true))
+	return err
+}
+
+// param is a pre-rendered <value>...</value> fragment for one method
+// argument.
+type param string
+
+func stringParam(s string) param {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return param(fmt.Sprintf("<value><string>%s</string></value>", buf.String()))
+}
+
+func boolParam(b bool) param {
+	v := "0"
+	if b {
+		v = "1"
+	}
+	return param(fmt.Sprintf("<value><boolean>%s</boolean></value>", v))
+}
+
+func (c *Client) call(method string, params ...param) (*xmlValue, error) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0"?><methodCall><methodName>`)
+	body.WriteString(method)
+	body.WriteString(`</methodName><params>`)
+	for _, p := range params {
+		body.WriteString("<param>")
+		body.WriteString(string(p))
+		body.WriteString("</param>")
+	}
+	body.WriteString(`</params></methodCall>`)
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseMethodResponse(respBody)
+}
+
+// xmlValue is a recursive representation of the XML-RPC <value> element,
+// covering only the variants supervisord's API actually returns.
+type xmlValue struct {
+	String  *string    `xml:"string"`
+	Int     *int       `xml:"int"`
+	I4      *int       `xml:"i4"`
+	Boolean *string    `xml:"boolean"`
+	Array   *xmlArray  `xml:"array"`
+	Struct  *xmlStruct `xml:"struct"`
+}
+
+func (v xmlValue) stringValue() string {
+	if v.String != nil {
+		return *v.String
+	}
+	return ""
+}
+
+func (v xmlValue) intValue() int {
+	if v.Int != nil {
+		return *v.Int
+	}
+	if v.I4 != nil {
+		return *v.I4
+	}
+	return 0
+}
+
+type xmlArray struct {
+	Data struct {
+		Values []xmlValue `xml:"value"`
+	} `xml:"data"`
+}
+
+type xmlStruct struct {
+	Members []xmlMember `xml:"member"`
+}
+
+func (s *xmlStruct) members() map[string]xmlValue {
+	result := make(map[string]xmlValue, len(s.Members))
+	for _, m := range s.Members {
+		result[m.Name] = m.Value
+	}
+	return result
+}
+
+type xmlMember struct {
+	Name  string   `xml:"name"`
+	Value xmlValue `xml:"value"`
+}
+
+type xmlMethodResponse struct {
+	Params *struct {
+		Param []struct {
+			Value xmlValue `xml:"value"`
+		} `xml:"param"`
+	} `xml:"params"`
+	Fault *struct {
+		Value xmlValue `xml:"value"`
+	} `xml:"fault"`
+}
+
+// parseMethodResponse decodes a supervisord XML-RPC response, surfacing a
+// <fault> as a Go error instead of a zero-value result.
+func parseMethodResponse(body []byte) (*xmlValue, error) {
+	var resp xmlMethodResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid XML-RPC response: %w", err)
+	}
+	if resp.Fault != nil {
+		members := map[string]xmlValue{}
+		if resp.Fault.Value.Struct != nil {
+			members = resp.Fault.Value.Struct.members()
+		}
+		return nil, fmt.Errorf("supervisor fault %s: %s",
+			strconv.Itoa(members["faultCode"].intValue()), members["faultString"].stringValue())
+	}
+	if resp.Params == nil || len(resp.Params.Param) == 0 {
+		return &xmlValue{}, nil
+	}
+	return &resp.Params.Param[0].Value, nil
+}
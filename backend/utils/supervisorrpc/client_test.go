@@ -0,0 +1,71 @@
+package supervisorrpc
+
+import "testing"
+
+func TestParseMethodResponseGetAllProcessInfo(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<methodResponse>
+<params>
+<param>
+<value><array><data>
+<value><struct>
+<member><name>name</name><value><string>app_00</string></value></member>
+<member><name>group</name><value><string>app</string></value></member>
+<member><name>statename</name><value><string>RUNNING</string></value></member>
+<member><name>pid</name><value><int>1234</int></value></member>
+<member><name>description</name><value><string>pid 1234, uptime 0:01:02</string></value></member>
+</struct></value>
+</data></array></value>
+</param>
+</params>
+</methodResponse>`)
+
+	value, err := parseMethodResponse(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Array == nil || len(value.Array.Data.Values) != 1 {
+		t.Fatalf("expected one process struct, got %+v", value)
+	}
+	members := value.Array.Data.Values[0].Struct.members()
+	if members["name"].stringValue() != "app_00" {
+		t.Fatalf("expected name app_00, got %q", members["name"].stringValue())
+	}
+	if members["pid"].intValue() != 1234 {
+		t.Fatalf("expected pid 1234, got %d", members["pid"].intValue())
+	}
+}
+
+func TestParseMethodResponseFault(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<methodResponse>
+<fault>
+<value><struct>
+<member><name>faultCode</name><value><int>10</int></value></member>
+<member><name>faultString</name><value><string>BAD_NAME: app</string></value></member>
+</struct></value>
+</fault>
+</methodResponse>`)
+
+	_, err := parseMethodResponse(body)
+	if err == nil {
+		t.Fatal("expected a fault to surface as an error")
+	}
+}
+
+func TestStringParamEscapesXML(t *testing.T) {
+	p := stringParam(`app & <b>"x"</b>`)
+	want := `<value><string>app &amp; &lt;b&gt;&#34;x&#34;&lt;/b&gt;</string></value>`
+	if string(p) != want {
+		t.Fatalf("expected escaped param %q, got %q", want, string(p))
+	}
+}
+
+func TestBoolParam(t *testing.T) {
+	if string(boolParam(true)) != "<value><boolean>1</boolean></value>" {
+		t.Fatalf("unexpected true param: %q", string(boolParam(true)))
+	}
+	if string(boolParam(false)) != "<value><boolean>0</boolean></value>" {
+		t.Fatalf("unexpected false param: %q", string(boolParam(false)))
+	}
+}
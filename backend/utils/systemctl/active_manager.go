@@ -0,0 +1,60 @@
+package systemctl
+
+import "os/exec"
+
+// activeManagerLookup resolves a binary on PATH; overridden in tests so
+// detection can be exercised without depending on what's actually installed.
+var activeManagerLookup = exec.LookPath
+
+// knownManagers lists every init system 1Panel supports, in the order
+// ActiveManager prefers them.
+var knownManagers = []struct {
+	binary string
+	name   string
+}{
+	{"systemctl", "systemd"},
+	{"rc-service", "openrc"},
+	{"service", "sysvinit"},
+}
+
+// ActiveManager reports the init system's control CLI detected on this host,
+// so callers (the UI, support logs) can show what 1Panel is actually driving
+// instead of assuming systemd. It checks, in order, systemd, OpenRC and
+// sysvinit's "service" wrapper; available is false when none of them are
+// found on PATH.
+func ActiveManager() (name string, available bool) {
+	for _, candidate := range knownManagers {
+		if _, err := activeManagerLookup(candidate.binary); err == nil {
+			return candidate.name, true
+		}
+	}
+	return "", false
+}
+
+// ManagerInfo describes one of the init systems 1Panel knows how to drive:
+// whether its control CLI is actually present on this host, and whether
+// it's the one ActiveManager would select.
+type ManagerInfo struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Active    bool   `json:"active"`
+}
+
+// ListManagers reports every init system 1Panel supports, whether each is
+// actually available on this host, and which one (at most one) is the
+// active one ActiveManager would pick. It's read-only and cheap: each
+// manager is checked with a single PATH lookup.
+func ListManagers() []ManagerInfo {
+	managers := make([]ManagerInfo, 0, len(knownManagers))
+	activeAssigned := false
+	for _, candidate := range knownManagers {
+		_, err := activeManagerLookup(candidate.binary)
+		available := err == nil
+		active := available && !activeAssigned
+		if active {
+			activeAssigned = true
+		}
+		managers = append(managers, ManagerInfo{Name: candidate.name, Available: available, Active: active})
+	}
+	return managers
+}
@@ -0,0 +1,105 @@
+package systemctl
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func withActiveManagerLookup(t *testing.T, lookup func(string) (string, error)) {
+	original := activeManagerLookup
+	activeManagerLookup = lookup
+	t.Cleanup(func() { activeManagerLookup = original })
+}
+
+func TestActiveManagerPrefersSystemd(t *testing.T) {
+	withActiveManagerLookup(t, func(name string) (string, error) {
+		return "/usr/bin/" + name, nil
+	})
+
+	name, available := ActiveManager()
+	if !available || name != "systemd" {
+		t.Fatalf("expected systemd to be detected, got %q available=%v", name, available)
+	}
+}
+
+func TestActiveManagerFallsBackToOpenrc(t *testing.T) {
+	withActiveManagerLookup(t, func(binary string) (string, error) {
+		if binary == "rc-service" {
+			return "/sbin/rc-service", nil
+		}
+		return "", exec.ErrNotFound
+	})
+
+	name, available := ActiveManager()
+	if !available || name != "openrc" {
+		t.Fatalf("expected openrc to be detected, got %q available=%v", name, available)
+	}
+}
+
+func TestActiveManagerFallsBackToSysvinit(t *testing.T) {
+	withActiveManagerLookup(t, func(binary string) (string, error) {
+		if binary == "service" {
+			return "/usr/sbin/service", nil
+		}
+		return "", exec.ErrNotFound
+	})
+
+	name, available := ActiveManager()
+	if !available || name != "sysvinit" {
+		t.Fatalf("expected sysvinit to be detected, got %q available=%v", name, available)
+	}
+}
+
+func TestActiveManagerReportsUnavailableWhenNothingFound(t *testing.T) {
+	withActiveManagerLookup(t, func(string) (string, error) {
+		return "", exec.ErrNotFound
+	})
+
+	name, available := ActiveManager()
+	if available || name != "" {
+		t.Fatalf("expected no manager to be detected, got %q available=%v", name, available)
+	}
+}
+
+func TestListManagersReflectsEveryKnownManagerWithExactlyOneActive(t *testing.T) {
+	withActiveManagerLookup(t, func(binary string) (string, error) {
+		if binary == "rc-service" || binary == "service" {
+			return "/sbin/" + binary, nil
+		}
+		return "", exec.ErrNotFound
+	})
+
+	managers := ListManagers()
+	if len(managers) != len(knownManagers) {
+		t.Fatalf("expected %d managers, got %d", len(knownManagers), len(managers))
+	}
+
+	activeName, activeAvailable := ActiveManager()
+	activeCount := 0
+	seen := map[string]ManagerInfo{}
+	for _, m := range managers {
+		seen[m.Name] = m
+		if m.Active {
+			activeCount++
+			if m.Name != activeName {
+				t.Fatalf("active manager mismatch: ListManagers said %q, ActiveManager said %q", m.Name, activeName)
+			}
+		}
+	}
+	if !activeAvailable {
+		t.Fatalf("expected ActiveManager to report availability when rc-service and service are present")
+	}
+	if activeCount != 1 {
+		t.Fatalf("expected exactly one active manager, got %d", activeCount)
+	}
+
+	if m, ok := seen["systemd"]; !ok || m.Available {
+		t.Fatalf("expected systemd to be listed and unavailable, got %+v ok=%v", m, ok)
+	}
+	if m, ok := seen["openrc"]; !ok || !m.Available || !m.Active {
+		t.Fatalf("expected openrc to be listed, available and active, got %+v ok=%v", m, ok)
+	}
+	if m, ok := seen["sysvinit"]; !ok || !m.Available || m.Active {
+		t.Fatalf("expected sysvinit to be listed, available and not active, got %+v ok=%v", m, ok)
+	}
+}
@@ -0,0 +1,133 @@
+package systemctl
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discoveryTTL controls how long a `list-unit-files` listing is reused
+// across FindServices calls before being re-fetched. It's a var, not a
+// const, so SetDiscoveryTTL can tune it (and tests can shrink it) without
+// waiting out the real default.
+var discoveryTTL = 5 * time.Minute
+
+// discoveryCacheMu guards discoveryCache, the raw `list-unit-files` output
+// cached across FindServices calls regardless of keyword, since the
+// underlying unit list is the same no matter what a caller searches for.
+var (
+	discoveryCacheMu      sync.Mutex
+	discoveryCacheOut     string
+	discoveryCacheErr     error
+	discoveryCacheExpires time.Time
+)
+
+// SetDiscoveryTTL overrides how long FindServices reuses a cached
+// `list-unit-files` listing before re-running it. Passing 0 effectively
+// disables caching (every call re-fetches).
+func SetDiscoveryTTL(d time.Duration) {
+	discoveryTTL = d
+}
+
+// FlushDiscoveryCache clears the cached unit listing, forcing the next
+// FindServices call to re-fetch it. Call this after anything that changes
+// the set of installed systemd units (e.g. installing or removing a
+// service), so a freshly installed service doesn't wait out discoveryTTL
+// before it shows up.
+func FlushDiscoveryCache() {
+	discoveryCacheMu.Lock()
+	discoveryCacheExpires = time.Time{}
+	discoveryCacheMu.Unlock()
+}
+
+// listUnitFiles runs `systemctl list-unit-files --type=service --no-legend`.
+// It's a var, not a direct call, so tests can inject a counting fake to
+// observe cache hits/misses without shelling out to a real systemctl.
+var listUnitFiles = func() (string, error) {
+	return RunSystemCtl("list-unit-files", "--type=service", "--no-legend")
+}
+
+// listUnitFilesCached returns listUnitFiles's output, reusing a cached copy
+// younger than discoveryTTL instead of re-running it on every FindServices
+// call.
+func listUnitFilesCached() (string, error) {
+	discoveryCacheMu.Lock()
+	defer discoveryCacheMu.Unlock()
+	if time.Now().Before(discoveryCacheExpires) {
+		return discoveryCacheOut, discoveryCacheErr
+	}
+	out, err := listUnitFiles()
+	discoveryCacheOut, discoveryCacheErr = out, err
+	discoveryCacheExpires = time.Now().Add(discoveryTTL)
+	return out, err
+}
+
+// FindServices lists every systemd service unit whose name contains keyword
+// (case-insensitive substring match; an empty keyword matches everything),
+// sorted deterministically: an exact match on keyword first, then
+// alphabetically. limit/offset page the result so a UI doesn't have to pull
+// every unit on a host with hundreds of them and paginate client-side;
+// internal callers that want the unbounded list can pass limit<=0.
+func FindServices(keyword string, limit, offset int) ([]string, error) {
+	out, err := listUnitFilesCached()
+	if err != nil {
+		if isSystemdUnavailable(out) {
+			return nil, ErrSystemdUnavailable
+		}
+		return nil, handlerErr(out, err)
+	}
+	matches := matchingServiceNames(out, keyword)
+	sortServiceMatches(matches, keyword)
+	return paginateServiceNames(matches, limit, offset), nil
+}
+
+// matchingServiceNames extracts unit names (with the ".service" suffix
+// stripped) from `systemctl list-unit-files --no-legend` output, keeping
+// only those whose name contains keyword.
+func matchingServiceNames(listUnitFilesOutput, keyword string) []string {
+	lowerKeyword := strings.ToLower(keyword)
+	var matches []string
+	for _, line := range strings.Split(listUnitFilesOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(fields[0], ".service")
+		if keyword == "" || strings.Contains(strings.ToLower(name), lowerKeyword) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
+
+// sortServiceMatches orders matches with an exact match on keyword first,
+// then alphabetically, so results are stable across calls regardless of the
+// order systemctl happened to print them in.
+func sortServiceMatches(matches []string, keyword string) {
+	sort.Slice(matches, func(i, j int) bool {
+		iExact := matches[i] == keyword
+		jExact := matches[j] == keyword
+		if iExact != jExact {
+			return iExact
+		}
+		return matches[i] < matches[j]
+	})
+}
+
+// paginateServiceNames applies limit/offset to an already-sorted list.
+// limit<=0 means unbounded; an offset past the end of items yields an empty
+// (non-nil) slice rather than an error.
+func paginateServiceNames(items []string, limit, offset int) []string {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []string{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
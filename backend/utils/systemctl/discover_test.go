@@ -0,0 +1,155 @@
+package systemctl
+
+import (
+	"testing"
+	"time"
+)
+
+// resetDiscoveryCache restores discoveryTTL/listUnitFiles to their defaults
+// and clears any cached listing, so discovery-cache tests don't leak state
+// into each other or into unrelated tests in this package.
+func resetDiscoveryCache(t *testing.T) {
+	t.Helper()
+	originalTTL := discoveryTTL
+	originalFetch := listUnitFiles
+	FlushDiscoveryCache()
+	t.Cleanup(func() {
+		discoveryTTL = originalTTL
+		listUnitFiles = originalFetch
+		FlushDiscoveryCache()
+	})
+}
+
+func TestListUnitFilesCachedReusesResultWithinTTL(t *testing.T) {
+	resetDiscoveryCache(t)
+	SetDiscoveryTTL(time.Minute)
+	calls := 0
+	listUnitFiles = func() (string, error) {
+		calls++
+		return "nginx.service enabled\n", nil
+	}
+	if _, err := listUnitFilesCached(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listUnitFilesCached(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the second call to be served from cache, got %d underlying calls", calls)
+	}
+}
+
+func TestFlushDiscoveryCacheForcesFreshFindServicesCall(t *testing.T) {
+	resetDiscoveryCache(t)
+	SetDiscoveryTTL(time.Minute)
+	calls := 0
+	listUnitFiles = func() (string, error) {
+		calls++
+		return "nginx.service enabled\n", nil
+	}
+	if _, err := FindServices("nginx", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindServices("nginx", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the second FindServices call to be served from cache, got %d underlying calls", calls)
+	}
+	FlushDiscoveryCache()
+	if _, err := FindServices("nginx", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected FlushDiscoveryCache to force a fresh call, got %d underlying calls", calls)
+	}
+}
+
+func TestListUnitFilesCachedRefetchesAfterTTLExpires(t *testing.T) {
+	resetDiscoveryCache(t)
+	SetDiscoveryTTL(time.Millisecond)
+	calls := 0
+	listUnitFiles = func() (string, error) {
+		calls++
+		return "nginx.service enabled\n", nil
+	}
+	if _, err := listUnitFilesCached(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := listUnitFilesCached(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the cache to expire and re-fetch, got %d underlying calls", calls)
+	}
+}
+
+func TestMatchingServiceNamesFiltersByKeyword(t *testing.T) {
+	output := "nginx.service                      enabled\nmysqld.service                     disabled\nnginx-extra.service                enabled\n"
+	got := matchingServiceNames(output, "nginx")
+	want := []string{"nginx", "nginx-extra"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMatchingServiceNamesEmptyKeywordMatchesAll(t *testing.T) {
+	output := "nginx.service    enabled\nmysqld.service   disabled\n"
+	got := matchingServiceNames(output, "")
+	if len(got) != 2 {
+		t.Fatalf("expected both units to match an empty keyword, got %v", got)
+	}
+}
+
+func TestSortServiceMatchesExactFirstThenAlphabetical(t *testing.T) {
+	matches := []string{"nginx-extra", "nginx", "nginxd"}
+	sortServiceMatches(matches, "nginx")
+	want := []string{"nginx", "nginx-extra", "nginxd"}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Fatalf("got %v, want %v", matches, want)
+		}
+	}
+}
+
+func TestSortServiceMatchesAlphabeticalWithoutExactMatch(t *testing.T) {
+	matches := []string{"zeta", "alpha", "beta"}
+	sortServiceMatches(matches, "nomatch")
+	want := []string{"alpha", "beta", "zeta"}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Fatalf("got %v, want %v", matches, want)
+		}
+	}
+}
+
+func TestPaginateServiceNamesAppliesLimitAndOffset(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	got := paginateServiceNames(items, 2, 1)
+	want := []string{"b", "c"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPaginateServiceNamesUnboundedWhenLimitIsZero(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	got := paginateServiceNames(items, 0, 0)
+	if len(got) != 3 {
+		t.Fatalf("expected all items with a zero limit, got %v", got)
+	}
+}
+
+func TestPaginateServiceNamesOffsetPastEndReturnsEmpty(t *testing.T) {
+	items := []string{"a", "b"}
+	got := paginateServiceNames(items, 10, 5)
+	if len(got) != 0 {
+		t.Fatalf("expected an empty result for an out-of-range offset, got %v", got)
+	}
+}
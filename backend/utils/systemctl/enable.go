@@ -0,0 +1,53 @@
+package systemctl
+
+// Enable, Disable and Start are idempotent: several init systems (and some
+// systemd versions) exit non-zero or print to stderr when a service is
+// already in the desired state, which would otherwise surface as a spurious
+// failure on a simple re-run. When the raw command reports an error, each
+// function falls back to checking the service's actual state and treats
+// already being there as success.
+
+func Enable(serviceName string) error {
+	return enable(serviceName, RunSystemCtl, IsEnable)
+}
+
+func enable(serviceName string, run func(...string) (string, error), isEnabled func(string) (bool, error)) error {
+	out, err := run("enable", serviceName)
+	if err == nil {
+		return nil
+	}
+	if enabled, checkErr := isEnabled(serviceName); checkErr == nil && enabled {
+		return nil
+	}
+	return handlerErr(out, err)
+}
+
+func Disable(serviceName string) error {
+	return disable(serviceName, RunSystemCtl, IsEnable)
+}
+
+func disable(serviceName string, run func(...string) (string, error), isEnabled func(string) (bool, error)) error {
+	out, err := run("disable", serviceName)
+	if err == nil {
+		return nil
+	}
+	if enabled, checkErr := isEnabled(serviceName); checkErr == nil && !enabled {
+		return nil
+	}
+	return handlerErr(out, err)
+}
+
+func Start(serviceName string) error {
+	return start(serviceName, RunSystemCtl, IsActive)
+}
+
+func start(serviceName string, run func(...string) (string, error), isActive func(string) (bool, error)) error {
+	out, err := run("start", serviceName)
+	if err == nil {
+		return nil
+	}
+	if active, checkErr := isActive(serviceName); checkErr == nil && active {
+		return nil
+	}
+	return handlerErr(out, err)
+}
@@ -0,0 +1,61 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnableSucceedsWhenAlreadyEnabled(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "Failed to enable: Unit already enabled.\n", errors.New("exit status 1")
+	}
+	isEnabled := func(string) (bool, error) { return true, nil }
+
+	if err := enable("nginx", run, isEnabled); err != nil {
+		t.Fatalf("expected idempotent success, got %v", err)
+	}
+}
+
+func TestEnablePropagatesRealFailure(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "Unit nginx.service not found.\n", errors.New("exit status 1")
+	}
+	isEnabled := func(string) (bool, error) { return false, nil }
+
+	if err := enable("nginx", run, isEnabled); err == nil {
+		t.Fatal("expected an error when the service genuinely failed to enable")
+	}
+}
+
+func TestDisableSucceedsWhenAlreadyDisabled(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "", errors.New("exit status 1")
+	}
+	isEnabled := func(string) (bool, error) { return false, nil }
+
+	if err := disable("nginx", run, isEnabled); err != nil {
+		t.Fatalf("expected idempotent success, got %v", err)
+	}
+}
+
+func TestStartSucceedsWhenAlreadyActive(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "", errors.New("exit status 1")
+	}
+	isActive := func(string) (bool, error) { return true, nil }
+
+	if err := start("nginx", run, isActive); err != nil {
+		t.Fatalf("expected idempotent success, got %v", err)
+	}
+}
+
+func TestStartPropagatesRealFailure(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "Job for nginx.service failed.\n", errors.New("exit status 1")
+	}
+	isActive := func(string) (bool, error) { return false, nil }
+
+	if err := start("nginx", run, isActive); err == nil {
+		t.Fatal("expected an error when the service genuinely failed to start")
+	}
+}
@@ -0,0 +1,103 @@
+package systemctl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+// ErrServiceNotFound is returned by FindServiceByPID/FindServiceByPort when
+// no systemd unit could be mapped back from the given PID/port, e.g. the
+// process isn't tracked by systemd at all.
+var ErrServiceNotFound = errors.New("no service found for the given identifier")
+
+// FindServiceByPID maps pid back to the systemd unit that owns it, so a
+// port-conflict diagnostic that only has a PID in hand (e.g. from `ss
+// -ltnp`) can report a friendly service name instead of a bare number.
+func FindServiceByPID(pid int) (string, error) {
+	return findServiceByPID(pid, RunSystemCtl)
+}
+
+func findServiceByPID(pid int, run func(...string) (string, error)) (string, error) {
+	out, _ := run("status", strconv.Itoa(pid))
+	if name, ok := parseUnitFromStatusOutput(out); ok {
+		return name, nil
+	}
+	return "", ErrServiceNotFound
+}
+
+// parseUnitFromStatusOutput pulls the unit name out of `systemctl status
+// <pid>`'s first line, e.g. "● nginx.service - A high performance web
+// server", which systemd only prints when it's actually tracking that PID.
+func parseUnitFromStatusOutput(out string) (string, bool) {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "●"))
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		name := fields[0]
+		if strings.HasSuffix(name, ".service") {
+			return strings.TrimSuffix(name, ".service"), true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// listeningSockets runs `ss -ltnp`, the standard way to list listening TCP
+// sockets along with the PID of the process holding each one.
+func listeningSockets() (string, error) {
+	return cmd.Exec("ss -ltnp")
+}
+
+// FindServiceByPort maps the process listening on port back to its owning
+// systemd unit: first `ss -ltnp` to find the PID bound to the port, then
+// FindServiceByPID to resolve that PID to a unit name.
+func FindServiceByPort(port int) (string, error) {
+	return findServiceByPort(port, listeningSockets, FindServiceByPID)
+}
+
+func findServiceByPort(port int, listListeners func() (string, error), resolvePID func(int) (string, error)) (string, error) {
+	out, err := listListeners()
+	if err != nil {
+		return "", err
+	}
+	pid, ok := parsePIDFromSSOutput(out, port)
+	if !ok {
+		return "", ErrServiceNotFound
+	}
+	return resolvePID(pid)
+}
+
+// ssListenPattern matches one `ss -ltnp` listening-socket line, capturing
+// the local port and the PID from its "pid=<n>" users: annotation, e.g.
+// "LISTEN 0 4096 0.0.0.0:8090 0.0.0.0:* users:((\"nginx\",pid=1234,fd=6))".
+var ssListenPattern = regexp.MustCompile(`:(\d+)\s+\S+\s+users:\(\([^)]*pid=(\d+)`)
+
+// parsePIDFromSSOutput finds the PID listening on port in `ss -ltnp`'s
+// output.
+func parsePIDFromSSOutput(out string, port int) (int, bool) {
+	wantPort := fmt.Sprintf(":%d", port)
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.Contains(line, wantPort) {
+			continue
+		}
+		matches := ssListenPattern.FindAllStringSubmatch(line, -1)
+		for _, m := range matches {
+			if m[1] != strconv.Itoa(port) {
+				continue
+			}
+			pid, err := strconv.Atoi(m[2])
+			if err != nil {
+				continue
+			}
+			return pid, true
+		}
+	}
+	return 0, false
+}
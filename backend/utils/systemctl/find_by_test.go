@@ -0,0 +1,65 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindServiceByPIDParsesUnitFromStatusOutput(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "● nginx.service - A high performance web server\n   Loaded: loaded\n   Active: active (running)\n", nil
+	}
+	name, err := findServiceByPID(1234, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "nginx" {
+		t.Fatalf("expected nginx, got %q", name)
+	}
+}
+
+func TestFindServiceByPIDReturnsNotFoundForUntrackedProcess(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		return "", errors.New("exit status 1")
+	}
+	if _, err := findServiceByPID(999999, run); err != ErrServiceNotFound {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestFindServiceByPortResolvesThroughListeningSocketAndPID(t *testing.T) {
+	listeners := func() (string, error) {
+		return "LISTEN 0 4096 0.0.0.0:8090 0.0.0.0:* users:((\"nginx\",pid=1234,fd=6))\n", nil
+	}
+	resolvePID := func(pid int) (string, error) {
+		if pid != 1234 {
+			t.Fatalf("unexpected pid %d", pid)
+		}
+		return "nginx", nil
+	}
+	name, err := findServiceByPort(8090, listeners, resolvePID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "nginx" {
+		t.Fatalf("expected nginx, got %q", name)
+	}
+}
+
+func TestFindServiceByPortReturnsNotFoundWhenPortNotListening(t *testing.T) {
+	listeners := func() (string, error) {
+		return "LISTEN 0 4096 0.0.0.0:80 0.0.0.0:* users:((\"nginx\",pid=1234,fd=6))\n", nil
+	}
+	resolvePID := func(int) (string, error) { t.Fatal("should not resolve a PID when the port isn't listening"); return "", nil }
+	if _, err := findServiceByPort(8090, listeners, resolvePID); err != ErrServiceNotFound {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestFindServiceByPortPropagatesListenerError(t *testing.T) {
+	listeners := func() (string, error) { return "", errors.New("ss: command not found") }
+	resolvePID := func(int) (string, error) { t.Fatal("should not resolve a PID when listing listeners failed"); return "", nil }
+	if _, err := findServiceByPort(8090, listeners, resolvePID); err == nil {
+		t.Fatal("expected an error when listing listening sockets fails")
+	}
+}
@@ -0,0 +1,35 @@
+package systemctl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HealthError reports that no supported init system's control CLI could be
+// found on this host, so service operations are falling back to whatever a
+// caller's noop/no-manager path does instead of actually driving one.
+type HealthError struct {
+	// Tried lists every manager name HealthCheck checked for, in the order
+	// ActiveManager prefers them.
+	Tried []string
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("no active service manager detected (tried: %s)", strings.Join(e.Tried, ", "))
+}
+
+// HealthCheck reports whether a real init-system manager is active on this
+// host. It returns nil when one is, or a *HealthError naming every manager
+// it tried when none is, so an external health check / readiness probe can
+// report degraded instead of only finding out when an operation against the
+// noop fallback fails.
+func HealthCheck() error {
+	if _, available := ActiveManager(); available {
+		return nil
+	}
+	tried := make([]string, 0, len(knownManagers))
+	for _, m := range knownManagers {
+		tried = append(tried, m.name)
+	}
+	return &HealthError{Tried: tried}
+}
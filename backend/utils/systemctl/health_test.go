@@ -0,0 +1,37 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHealthCheckReturnsNilWhenAManagerIsActive(t *testing.T) {
+	withActiveManagerLookup(t, func(binary string) (string, error) {
+		return "/usr/bin/" + binary, nil
+	})
+
+	if err := HealthCheck(); err != nil {
+		t.Fatalf("expected a nil error when a manager is active, got %v", err)
+	}
+}
+
+func TestHealthCheckReportsDegradedStateWithTriedManagers(t *testing.T) {
+	withActiveManagerLookup(t, func(string) (string, error) {
+		return "", errors.New("not found")
+	})
+
+	err := HealthCheck()
+	if err == nil {
+		t.Fatal("expected a degraded error when no manager is active")
+	}
+	healthErr, ok := err.(*HealthError)
+	if !ok {
+		t.Fatalf("expected a *HealthError, got %T", err)
+	}
+	if len(healthErr.Tried) != len(knownManagers) {
+		t.Fatalf("expected all %d known managers to be listed as tried, got %v", len(knownManagers), healthErr.Tried)
+	}
+	if healthErr.Error() == "" {
+		t.Fatal("expected a non-empty error message")
+	}
+}
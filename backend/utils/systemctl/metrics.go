@@ -0,0 +1,90 @@
+package systemctl
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metricsEnabled gates all metrics recording behind a single atomic load, so
+// a router with metrics scraping disabled pays nothing beyond that load on
+// every systemctl invocation.
+var metricsEnabled atomic.Bool
+
+// EnableMetrics turns on command-duration recording for RunSystemCtl.
+func EnableMetrics() { metricsEnabled.Store(true) }
+
+// DisableMetrics turns off command-duration recording and leaves any
+// already-accumulated data in place.
+func DisableMetrics() { metricsEnabled.Store(false) }
+
+// durationBucketsSeconds are the histogram's upper bounds, close to
+// Prometheus's own default buckets so a Metrics() snapshot looks familiar to
+// anyone used to scraping a Prometheus histogram.
+var durationBucketsSeconds = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type commandDuration struct {
+	count    uint64
+	sumNanos uint64
+	buckets  []uint64 // parallel to durationBucketsSeconds, cumulative per Prometheus convention
+}
+
+var (
+	commandDurationMu sync.Mutex
+	commandDurationOp = map[string]*commandDuration{}
+)
+
+// recordCommandDuration records one RunSystemCtl call's duration under op,
+// which is the systemctl subcommand (e.g. "restart", "is-active").
+func recordCommandDuration(op string, d time.Duration) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	commandDurationMu.Lock()
+	defer commandDurationMu.Unlock()
+	m, ok := commandDurationOp[op]
+	if !ok {
+		m = &commandDuration{buckets: make([]uint64, len(durationBucketsSeconds))}
+		commandDurationOp[op] = m
+	}
+	m.count++
+	m.sumNanos += uint64(d.Nanoseconds())
+	seconds := d.Seconds()
+	for i, upper := range durationBucketsSeconds {
+		if seconds <= upper {
+			m.buckets[i]++
+		}
+	}
+}
+
+// CommandDurationSnapshot is a point-in-time copy of one systemctl
+// subcommand's accumulated duration histogram.
+type CommandDurationSnapshot struct {
+	Count      uint64
+	SumSeconds float64
+	// Buckets maps a histogram upper bound (seconds) to the cumulative
+	// count of calls at or under it, matching Prometheus's "le" buckets.
+	Buckets map[float64]uint64
+}
+
+// Metrics returns a snapshot of command-duration histograms recorded since
+// metrics were enabled, keyed by systemctl subcommand. It's safe to call
+// whether or not metrics are enabled; it's empty until something has been
+// recorded, and still holds earlier data after DisableMetrics.
+func Metrics() map[string]CommandDurationSnapshot {
+	commandDurationMu.Lock()
+	defer commandDurationMu.Unlock()
+	snapshot := make(map[string]CommandDurationSnapshot, len(commandDurationOp))
+	for op, m := range commandDurationOp {
+		buckets := make(map[float64]uint64, len(durationBucketsSeconds))
+		for i, upper := range durationBucketsSeconds {
+			buckets[upper] = m.buckets[i]
+		}
+		snapshot[op] = CommandDurationSnapshot{
+			Count:      m.count,
+			SumSeconds: float64(m.sumNanos) / 1e9,
+			Buckets:    buckets,
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,36 @@
+package systemctl
+
+import "testing"
+
+func TestRecordCommandDurationNoopWhenDisabled(t *testing.T) {
+	DisableMetrics()
+	commandDurationMu.Lock()
+	commandDurationOp = map[string]*commandDuration{}
+	commandDurationMu.Unlock()
+
+	recordCommandDuration("restart", 0)
+
+	if len(Metrics()) != 0 {
+		t.Fatal("expected no metrics to be recorded while disabled")
+	}
+}
+
+func TestRecordCommandDurationAccumulatesWhenEnabled(t *testing.T) {
+	EnableMetrics()
+	defer DisableMetrics()
+	commandDurationMu.Lock()
+	commandDurationOp = map[string]*commandDuration{}
+	commandDurationMu.Unlock()
+
+	recordCommandDuration("restart", 0)
+	recordCommandDuration("restart", 0)
+
+	snapshot := Metrics()
+	got, ok := snapshot["restart"]
+	if !ok {
+		t.Fatal("expected a snapshot entry for \"restart\"")
+	}
+	if got.Count != 2 {
+		t.Fatalf("expected count 2, got %d", got.Count)
+	}
+}
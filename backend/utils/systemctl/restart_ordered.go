@@ -0,0 +1,64 @@
+package systemctl
+
+import (
+	"fmt"
+	"time"
+)
+
+// restartOrderedPoll/Timeout bound how long RestartOrdered waits for each
+// service to report active before moving on to the next one in line.
+var (
+	restartOrderedPoll    = 500 * time.Millisecond
+	restartOrderedTimeout = 30 * time.Second
+)
+
+// RestartOrdered restarts services in the given order, waiting for each one
+// to report active before restarting the next, and aborts with context on
+// the first failure. This matters for stacks like supervisord-then-its-
+// processes or nginx-after-php-fpm, where restarting out of order leaves a
+// dependent service briefly unable to reach the thing it depends on. Pass
+// reverse=true to walk the list back-to-front, for stop ordering.
+func RestartOrdered(services []string, reverse bool) error {
+	return restartOrdered(services, reverse, Restart, IsActive)
+}
+
+func restartOrdered(services []string, reverse bool, restart func(string) error, isActive func(string) (bool, error)) error {
+	ordered := orderServices(services, reverse)
+	for _, serviceName := range ordered {
+		if err := restart(serviceName); err != nil {
+			return fmt.Errorf("restart %s failed: %w", serviceName, err)
+		}
+		if err := waitForActive(serviceName, isActive); err != nil {
+			return fmt.Errorf("%s did not become active after restart: %w", serviceName, err)
+		}
+	}
+	return nil
+}
+
+// orderServices returns services in restart order, or reversed for stop
+// ordering, without mutating the caller's slice.
+func orderServices(services []string, reverse bool) []string {
+	if !reverse {
+		return services
+	}
+	ordered := make([]string, len(services))
+	for i, s := range services {
+		ordered[len(services)-1-i] = s
+	}
+	return ordered
+}
+
+// waitForActive polls isActive(serviceName) until it reports active or
+// restartOrderedTimeout elapses.
+func waitForActive(serviceName string, isActive func(string) (bool, error)) error {
+	deadline := time.Now().Add(restartOrderedTimeout)
+	for {
+		if active, err := isActive(serviceName); err == nil && active {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s to become active", serviceName)
+		}
+		time.Sleep(restartOrderedPoll)
+	}
+}
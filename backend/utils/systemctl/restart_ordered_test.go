@@ -0,0 +1,118 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func withFastRestartPolling(t *testing.T) {
+	t.Helper()
+	origPoll, origTimeout := restartOrderedPoll, restartOrderedTimeout
+	restartOrderedPoll = time.Millisecond
+	restartOrderedTimeout = 50 * time.Millisecond
+	t.Cleanup(func() {
+		restartOrderedPoll, restartOrderedTimeout = origPoll, origTimeout
+	})
+}
+
+func TestRestartOrderedRestartsInOrder(t *testing.T) {
+	withFastRestartPolling(t)
+
+	var restarted []string
+	restart := func(name string) error {
+		restarted = append(restarted, name)
+		return nil
+	}
+	isActive := func(string) (bool, error) { return true, nil }
+
+	if err := restartOrdered([]string{"supervisord", "app1", "app2"}, false, restart, isActive); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"supervisord", "app1", "app2"}
+	if len(restarted) != len(want) {
+		t.Fatalf("expected %v, got %v", want, restarted)
+	}
+	for i := range want {
+		if restarted[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, restarted)
+		}
+	}
+}
+
+func TestRestartOrderedReverseForStop(t *testing.T) {
+	withFastRestartPolling(t)
+
+	var restarted []string
+	restart := func(name string) error {
+		restarted = append(restarted, name)
+		return nil
+	}
+	isActive := func(string) (bool, error) { return true, nil }
+
+	if err := restartOrdered([]string{"supervisord", "app1", "app2"}, true, restart, isActive); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"app2", "app1", "supervisord"}
+	for i := range want {
+		if restarted[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, restarted)
+		}
+	}
+}
+
+func TestRestartOrderedWaitsForActiveBeforeNext(t *testing.T) {
+	withFastRestartPolling(t)
+
+	var restarted []string
+	activeCalls := 0
+	restart := func(name string) error {
+		restarted = append(restarted, name)
+		return nil
+	}
+	isActive := func(name string) (bool, error) {
+		activeCalls++
+		// app1 only becomes active on its third poll.
+		return name != "app1" || activeCalls >= 3, nil
+	}
+
+	if err := restartOrdered([]string{"app1", "app2"}, false, restart, isActive); err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted) != 2 || restarted[1] != "app2" {
+		t.Fatalf("expected app2 to restart only after app1 became active, got %v", restarted)
+	}
+}
+
+func TestRestartOrderedAbortsOnFirstFailure(t *testing.T) {
+	withFastRestartPolling(t)
+
+	var restarted []string
+	restart := func(name string) error {
+		restarted = append(restarted, name)
+		if name == "supervisord" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	isActive := func(string) (bool, error) { return true, nil }
+
+	err := restartOrdered([]string{"supervisord", "app1"}, false, restart, isActive)
+	if err == nil {
+		t.Fatal("expected an error from the failing restart")
+	}
+	if len(restarted) != 1 {
+		t.Fatalf("expected app1 to never be restarted after supervisord failed, got %v", restarted)
+	}
+}
+
+func TestRestartOrderedTimesOutWaitingForActive(t *testing.T) {
+	withFastRestartPolling(t)
+
+	restart := func(string) error { return nil }
+	isActive := func(string) (bool, error) { return false, nil }
+
+	if err := restartOrdered([]string{"app1"}, false, restart, isActive); err == nil {
+		t.Fatal("expected a timeout error when the service never becomes active")
+	}
+}
@@ -0,0 +1,62 @@
+package systemctl
+
+import (
+	"time"
+
+	"github.com/1Panel-dev/1Panel/backend/global"
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+// selfServiceUnit is the unit name 1Panel registers itself under on a
+// systemd host.
+const selfServiceUnit = "1panel.service"
+
+// selfRestartFallback is the shell chain upgrade, rollback and restore used
+// to hand-roll individually before RestartSelf existed: enable and restart
+// via the sysvinit-style "service" wrapper first (what the installer sets
+// up on non-systemd hosts), running `systemctl daemon-reload` only if that
+// fails. sh groups `a && b || c && d` left to right, so the final
+// `systemctl restart` runs in either case.
+const selfRestartFallback = "service 1paneld enable && service 1paneld restart || systemctl daemon-reload && systemctl restart " + selfServiceUnit
+
+// restartSelfFn and restartSelfFallbackFn are overridden in tests so
+// RestartSelf's fallback ordering can be exercised without actually
+// restarting anything.
+var restartSelfFn = func() error {
+	return Restart(selfServiceUnit)
+}
+
+var restartSelfFallbackFn = func() error {
+	_, err := cmd.ExecWithTimeOut(selfRestartFallback, 1*time.Minute)
+	return err
+}
+
+// logRestartSelfManagerFailed and logRestartSelfFallbackFailed report a
+// RestartSelf fallback; overridden in tests since global.LOG isn't
+// initialized outside the running server.
+var logRestartSelfManagerFailed = func(err error) {
+	global.LOG.Warnf("restart 1panel via the detected service manager failed, falling back to shell chain: %v", err)
+}
+
+var logRestartSelfFallbackFailed = func(err error) {
+	global.LOG.Errorf("restart 1panel via fallback shell chain failed: %v", err)
+}
+
+// RestartSelf restarts the running 1Panel service through the detected
+// manager, falling back to the historical service/systemctl shell chain
+// only when the manager path fails (e.g. systemctl is on PATH but can't
+// reach systemd). It logs a warning when the manager path fails and an
+// error when the fallback fails too, so a restart failure during upgrade,
+// rollback or restore is easy to diagnose.
+func RestartSelf() error {
+	if err := restartSelfFn(); err == nil {
+		return nil
+	} else {
+		logRestartSelfManagerFailed(err)
+	}
+	if err := restartSelfFallbackFn(); err != nil {
+		logRestartSelfFallbackFailed(err)
+		return err
+	}
+	return nil
+}
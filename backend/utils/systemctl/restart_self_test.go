@@ -0,0 +1,74 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func withRestartSelfFns(t *testing.T, restart, fallback func() error) {
+	t.Helper()
+	originalRestart := restartSelfFn
+	originalFallback := restartSelfFallbackFn
+	originalLogManagerFailed := logRestartSelfManagerFailed
+	originalLogFallbackFailed := logRestartSelfFallbackFailed
+	restartSelfFn = restart
+	restartSelfFallbackFn = fallback
+	logRestartSelfManagerFailed = func(error) {}
+	logRestartSelfFallbackFailed = func(error) {}
+	t.Cleanup(func() {
+		restartSelfFn = originalRestart
+		restartSelfFallbackFn = originalFallback
+		logRestartSelfManagerFailed = originalLogManagerFailed
+		logRestartSelfFallbackFailed = originalLogFallbackFailed
+	})
+}
+
+func TestRestartSelfUsesManagerPathWhenItSucceeds(t *testing.T) {
+	fallbackCalled := false
+	withRestartSelfFns(t, func() error {
+		return nil
+	}, func() error {
+		fallbackCalled = true
+		return nil
+	})
+
+	if err := RestartSelf(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if fallbackCalled {
+		t.Fatal("expected the fallback shell chain not to run when the manager path succeeds")
+	}
+}
+
+func TestRestartSelfFallsBackWhenManagerPathFails(t *testing.T) {
+	fallbackCalled := false
+	withRestartSelfFns(t, func() error {
+		return errors.New("no such unit")
+	}, func() error {
+		fallbackCalled = true
+		return nil
+	})
+
+	if err := RestartSelf(); err != nil {
+		t.Fatalf("expected no error once the fallback succeeds, got %v", err)
+	}
+	if !fallbackCalled {
+		t.Fatal("expected the fallback shell chain to run when the manager path fails")
+	}
+}
+
+func TestRestartSelfReturnsFallbackErrorWhenBothPathsFail(t *testing.T) {
+	withRestartSelfFns(t, func() error {
+		return errors.New("no such unit")
+	}, func() error {
+		return errors.New("service command not found")
+	})
+
+	err := RestartSelf()
+	if err == nil {
+		t.Fatal("expected an error when both the manager path and the fallback fail")
+	}
+	if err.Error() != "service command not found" {
+		t.Fatalf("expected the fallback's error to be returned, got %v", err)
+	}
+}
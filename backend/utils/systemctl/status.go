@@ -0,0 +1,55 @@
+package systemctl
+
+import "strings"
+
+// ServiceStatus reports a unit's current state along with whether it has
+// already exited successfully, so a caller can tell a oneshot unit that ran
+// to completion (e.g. a one-off update job) apart from one that failed or
+// never started -- both show up as "inactive" to a plain IsActive check.
+type ServiceStatus struct {
+	Active bool
+	// ExitedSuccessfully is true for a unit that ran to completion without
+	// error, whether it's still active (a long-running service) or already
+	// back to inactive (a oneshot unit).
+	ExitedSuccessfully bool
+}
+
+// Status returns serviceName's ServiceStatus using `systemctl show`, whose
+// key=value property output -- unlike `systemctl status`'s free-form text --
+// uses fixed English tokens regardless of the host's locale.
+func Status(serviceName string) (ServiceStatus, error) {
+	return status(serviceName, RunSystemCtl)
+}
+
+func status(serviceName string, run func(...string) (string, error)) (ServiceStatus, error) {
+	out, err := run("show", serviceName, "--property=ActiveState,Result")
+	if err != nil {
+		if isSystemdUnavailable(out) {
+			return ServiceStatus{}, ErrSystemdUnavailable
+		}
+		return ServiceStatus{}, err
+	}
+	return parseStatusShowOutput(out), nil
+}
+
+// parseStatusShowOutput parses the "Key=Value" lines `systemctl show
+// --property=...` prints, one per requested property.
+func parseStatusShowOutput(out string) ServiceStatus {
+	props := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		props[key] = value
+	}
+	active := props["ActiveState"] == "active"
+	return ServiceStatus{
+		Active: active,
+		// Result=success covers both a still-active long-running service
+		// and a oneshot unit that ran to completion and went back to
+		// inactive; anything else (e.g. "exit-code", "timeout") means the
+		// last run failed.
+		ExitedSuccessfully: active || props["Result"] == "success",
+	}
+}
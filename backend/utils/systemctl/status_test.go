@@ -0,0 +1,42 @@
+package systemctl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseStatusShowOutputActiveService(t *testing.T) {
+	out := "ActiveState=active\nResult=success\n"
+	status := parseStatusShowOutput(out)
+	if !status.Active || !status.ExitedSuccessfully {
+		t.Fatalf("expected an active service to be active and exited successfully, got %+v", status)
+	}
+}
+
+func TestParseStatusShowOutputOneshotCompletedSuccessfully(t *testing.T) {
+	out := "ActiveState=inactive\nResult=success\n"
+	status := parseStatusShowOutput(out)
+	if status.Active {
+		t.Fatal("expected a completed oneshot unit to be reported as not active")
+	}
+	if !status.ExitedSuccessfully {
+		t.Fatal("expected a completed oneshot unit to be reported as exited successfully")
+	}
+}
+
+func TestParseStatusShowOutputFailedUnit(t *testing.T) {
+	out := "ActiveState=failed\nResult=exit-code\n"
+	status := parseStatusShowOutput(out)
+	if status.Active || status.ExitedSuccessfully {
+		t.Fatalf("expected a failed unit to be neither active nor exited successfully, got %+v", status)
+	}
+}
+
+func TestStatusReturnsErrSystemdUnavailable(t *testing.T) {
+	run := func(...string) (string, error) {
+		return "Failed to connect to bus: No such file or directory\n", errors.New("exit status 1")
+	}
+	if _, err := status("clamav-freshclam", run); err != ErrSystemdUnavailable {
+		t.Fatalf("expected ErrSystemdUnavailable, got %v", err)
+	}
+}
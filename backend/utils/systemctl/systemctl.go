@@ -2,14 +2,36 @@ package systemctl
 
 import (
 	"fmt"
-	"github.com/pkg/errors"
 	"os/exec"
 	"strings"
+	"time"
+
+	"github.com/1Panel-dev/1Panel/backend/global"
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+	"github.com/pkg/errors"
 )
 
+// logSystemctlDebug logs the assembled "systemctl <args>" invocation and its
+// outcome when cmd.DebugLoggingEnabled, mirroring cmd.Execf's own debug
+// logging for the one systemctl call site that runs exec.Command directly
+// instead of going through the cmd package. It's a var, not a direct
+// global.LOG call, so tests can capture what would be logged without
+// needing global.LOG initialized outside the running server.
+var logSystemctlDebug = func(cmdStr, output string, err error) {
+	if !cmd.DebugLoggingEnabled() {
+		return
+	}
+	global.LOG.Debugf("exec %q output=%q err=%v", cmdStr, output, err)
+}
+
 func RunSystemCtl(args ...string) (string, error) {
-	cmd := exec.Command("systemctl", args...)
-	output, err := cmd.CombinedOutput()
+	start := time.Now()
+	execCmd := exec.Command("systemctl", args...)
+	output, err := execCmd.CombinedOutput()
+	if len(args) > 0 {
+		recordCommandDuration(args[0], time.Since(start))
+	}
+	logSystemctlDebug("systemctl "+strings.Join(args, " "), string(output), err)
 	if err != nil {
 		return string(output), fmt.Errorf("failed to run command: %w", err)
 	}
@@ -19,6 +41,9 @@ func RunSystemCtl(args ...string) (string, error) {
 func IsActive(serviceName string) (bool, error) {
 	out, err := RunSystemCtl("is-active", serviceName)
 	if err != nil {
+		if isSystemdUnavailable(out) {
+			return false, ErrSystemdUnavailable
+		}
 		return false, err
 	}
 	return out == "active\n", nil
@@ -27,6 +52,9 @@ func IsActive(serviceName string) (bool, error) {
 func IsEnable(serviceName string) (bool, error) {
 	out, err := RunSystemCtl("is-enabled", serviceName)
 	if err != nil {
+		if isSystemdUnavailable(out) {
+			return false, ErrSystemdUnavailable
+		}
 		return false, err
 	}
 	return out == "enabled\n", nil
@@ -43,8 +71,32 @@ func IsExist(serviceName string) (bool, error) {
 	return true, nil
 }
 
+// ErrSystemdUnavailable is returned in place of the raw systemctl output
+// when systemctl can't reach systemd at all, which is the case inside most
+// containers since they don't run an init system.
+var ErrSystemdUnavailable = errors.New("systemd is not available in this environment")
+
+// systemdUnavailableMarkers are substrings systemctl prints to stderr when
+// there's no systemd instance for it to talk to.
+var systemdUnavailableMarkers = []string{
+	"Failed to connect to bus",
+	"System has not been booted with systemd",
+}
+
+func isSystemdUnavailable(out string) bool {
+	for _, marker := range systemdUnavailableMarkers {
+		if strings.Contains(out, marker) {
+			return true
+		}
+	}
+	return false
+}
+
 func handlerErr(out string, err error) error {
 	if err != nil {
+		if isSystemdUnavailable(out) {
+			return ErrSystemdUnavailable
+		}
 		if out != "" {
 			return errors.New(out)
 		}
@@ -62,3 +114,28 @@ func Operate(operate, serviceName string) error {
 	out, err := RunSystemCtl(operate, serviceName)
 	return handlerErr(out, err)
 }
+
+// customActionArgsWhitelist maps each action verb to the extra flags callers
+// are allowed to pass through CustomActionArgs, so a custom action can't be
+// used to smuggle arbitrary systemctl options onto the command line.
+var customActionArgsWhitelist = map[string]map[string]bool{
+	"restart": {"--no-block": true},
+	"stop":    {"--no-block": true},
+	"start":   {"--no-block": true},
+}
+
+// CustomActionArgs runs operate against serviceName with extra CLI flags,
+// e.g. "restart" with "--no-block". Each arg must be on operate's whitelist;
+// anything else is rejected instead of being passed through to the shell.
+func CustomActionArgs(operate string, args []string, serviceName string) error {
+	allowed := customActionArgsWhitelist[operate]
+	for _, arg := range args {
+		if !allowed[arg] {
+			return fmt.Errorf("argument %q is not whitelisted for action %q", arg, operate)
+		}
+	}
+	cmdArgs := append([]string{operate}, args...)
+	cmdArgs = append(cmdArgs, serviceName)
+	out, err := RunSystemCtl(cmdArgs...)
+	return handlerErr(out, err)
+}
@@ -0,0 +1,73 @@
+package systemctl
+
+import (
+	"testing"
+
+	"github.com/1Panel-dev/1Panel/backend/utils/cmd"
+)
+
+func TestIsSystemdUnavailable(t *testing.T) {
+	cases := map[string]bool{
+		"Failed to connect to bus: No such file or directory\n":            true,
+		"System has not been booted with systemd as init system (PID 1)\n": true,
+		"Unit foo.service could not be found.\n":                           false,
+		"":                                                                 false,
+	}
+	for out, want := range cases {
+		if got := isSystemdUnavailable(out); got != want {
+			t.Errorf("isSystemdUnavailable(%q) = %v, want %v", out, got, want)
+		}
+	}
+}
+
+func TestRunSystemCtlLogsAssembledArgsWhenDebugEnabled(t *testing.T) {
+	var captured []string
+	origLog := logSystemctlDebug
+	logSystemctlDebug = func(cmdStr, output string, err error) {
+		if !cmd.DebugLoggingEnabled() {
+			return
+		}
+		captured = append(captured, cmdStr)
+	}
+	t.Cleanup(func() { logSystemctlDebug = origLog })
+	t.Setenv(cmd.DebugLoggingEnvVar, "1")
+
+	_, _ = RunSystemCtl("is-active", "nginx")
+
+	if len(captured) != 1 || captured[0] != "systemctl is-active nginx" {
+		t.Fatalf("expected the assembled command to be logged once, got %v", captured)
+	}
+}
+
+func TestRunSystemCtlDoesNotLogWhenDebugDisabled(t *testing.T) {
+	var captured []string
+	origLog := logSystemctlDebug
+	logSystemctlDebug = func(cmdStr, output string, err error) {
+		if !cmd.DebugLoggingEnabled() {
+			return
+		}
+		captured = append(captured, cmdStr)
+	}
+	t.Cleanup(func() { logSystemctlDebug = origLog })
+	t.Setenv(cmd.DebugLoggingEnvVar, "0")
+
+	_, _ = RunSystemCtl("is-active", "nginx")
+
+	if len(captured) != 0 {
+		t.Fatalf("expected no logging when debug mode is off, got %v", captured)
+	}
+}
+
+func TestCustomActionArgsRejectsUnwhitelistedArg(t *testing.T) {
+	err := CustomActionArgs("restart", []string{"--force"}, "nginx")
+	if err == nil {
+		t.Fatal("expected an unwhitelisted arg to be rejected")
+	}
+}
+
+func TestCustomActionArgsRejectsUnwhitelistedAction(t *testing.T) {
+	err := CustomActionArgs("enable", []string{"--no-block"}, "nginx")
+	if err == nil {
+		t.Fatal("expected an action with no whitelist entries to reject any arg")
+	}
+}
@@ -16,9 +16,14 @@ import (
 )
 
 func init() {
+	restoreCmd.Flags().BoolVar(&noRestartAfterRestore, "no-restart", false, "回滚后不自动重启 1Panel 服务，需手动重启")
 	RootCmd.AddCommand(restoreCmd)
 }
 
+// noRestartAfterRestore skips the automatic service restart performRollback
+// does after restoring files, for users who want to restart manually.
+var noRestartAfterRestore bool
+
 var restoreCmd = &cobra.Command{
 	Use:   "restore",
 	Short: "回滚 1Panel 服务及数据",
@@ -66,10 +71,40 @@ func performRollback(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("回滚成功！正在重启服务，请稍候...")
+	fmt.Println("回滚成功！")
+	if noRestartAfterRestore {
+		fmt.Println("已跳过自动重启，请手动重启 1Panel 服务")
+		return nil
+	}
+	fmt.Println("正在重启服务，请稍候...")
+	if err := restartServiceAfterRestore(serviceTarget); err != nil {
+		fmt.Printf("服务重启失败，请手动重启 1Panel 服务: %v\n", err)
+		return nil
+	}
+	fmt.Println("服务重启成功")
 	return nil
 }
 
+// restartServiceAfterRestore restarts 1Panel's service using whichever init
+// system serviceTarget was just restored for (sysvinit's /etc/init.d or a
+// systemd unit), so a rollback leaves the restored binaries actually
+// running instead of waiting for a manual restart. It's robust when the
+// service file it expects to restart isn't actually there.
+func restartServiceAfterRestore(serviceTarget string) error {
+	if _, err := os.Stat(serviceTarget); err != nil {
+		return fmt.Errorf("服务文件 %s 不存在，无法重启", serviceTarget)
+	}
+	if strings.Contains(serviceTarget, "init.d") {
+		_, err := cmdUtils.Exec("service 1paneld restart")
+		return err
+	}
+	if _, err := cmdUtils.Exec("systemctl daemon-reload"); err != nil {
+		return err
+	}
+	_, err := cmdUtils.Exec("systemctl restart 1panel")
+	return err
+}
+
 func getBaseDir() (string, error) {
 	stdout, err := cmdUtils.Exec("grep '^BASE_DIR=' /usr/local/bin/1pctl | cut -d'=' -f2")
 	if err != nil {